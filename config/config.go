@@ -43,6 +43,15 @@ type SentryConfig struct {
 	Dsn string
 }
 
+type FilterConfig struct {
+	AllowedNamespaces       []string
+	DeniedNamespaces        []string
+	LabelSelector           string
+	IgnoreStatusOnlyUpdates bool
+	SignificantChangesOnly  bool
+	WatchedAnnotations      []string
+}
+
 type CommonServiceConfig struct {
 	DebugLabelValue         string
 	MaxConcurrentReconciles int
@@ -63,6 +72,8 @@ type CommonServiceConfig struct {
 
 	LeaderElectionEnabled bool
 
+	Filter FilterConfig
+
 	Sentry SentryConfig
 }
 
@@ -103,6 +114,11 @@ func NewDefaultConfig() *CommonServiceConfig {
 
 		LeaderElectionEnabled: false,
 
+		Filter: FilterConfig{
+			IgnoreStatusOnlyUpdates: false,
+			SignificantChangesOnly:  false,
+		},
+
 		Sentry: SentryConfig{
 			Dsn: os.Getenv("SENTRY_DSN"),
 		},
@@ -138,4 +154,11 @@ func (c *CommonServiceConfig) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&c.HealthProbeBindAddress, "health-probe-bind-address", c.HealthProbeBindAddress, "Set the health probe bind address")
 
 	fs.BoolVar(&c.LeaderElectionEnabled, "leader-elect", c.LeaderElectionEnabled, "Enable leader election for the controller manager")
+
+	fs.StringSliceVar(&c.Filter.AllowedNamespaces, "filter-allowed-namespaces", c.Filter.AllowedNamespaces, "Only reconcile resources in these namespaces, all namespaces if empty")
+	fs.StringSliceVar(&c.Filter.DeniedNamespaces, "filter-denied-namespaces", c.Filter.DeniedNamespaces, "Never reconcile resources in these namespaces")
+	fs.StringVar(&c.Filter.LabelSelector, "filter-label-selector", c.Filter.LabelSelector, "Only reconcile resources matching the label selector, e.g. team=a,!legacy")
+	fs.BoolVar(&c.Filter.IgnoreStatusOnlyUpdates, "filter-ignore-status-only-updates", c.Filter.IgnoreStatusOnlyUpdates, "Ignore updates only changing the status of resources")
+	fs.BoolVar(&c.Filter.SignificantChangesOnly, "filter-significant-changes-only", c.Filter.SignificantChangesOnly, "Only reconcile updates changing the generation, labels, finalizers or watched annotations of resources")
+	fs.StringSliceVar(&c.Filter.WatchedAnnotations, "filter-watched-annotations", c.Filter.WatchedAnnotations, "Annotations whose changes are significant when only significant changes are reconciled")
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/platform-mesh/golang-commons/config"
@@ -31,3 +32,28 @@ func TestNewDefaultConfig(t *testing.T) {
 	assert.Equal(t, time.Minute, cfg.ShutdownTimeout)
 	assert.True(t, cfg.EnableHTTP2)
 }
+
+func TestAddFlags_Filter(t *testing.T) {
+	cfg := config.NewDefaultConfig()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	cfg.AddFlags(fs)
+
+	err := fs.Parse([]string{
+		"--filter-allowed-namespaces=a,b",
+		"--filter-denied-namespaces=kube-system",
+		"--filter-label-selector=team=a",
+		"--filter-ignore-status-only-updates",
+		"--filter-significant-changes-only",
+		"--filter-watched-annotations=refresh",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, config.FilterConfig{
+		AllowedNamespaces:       []string{"a", "b"},
+		DeniedNamespaces:        []string{"kube-system"},
+		LabelSelector:           "team=a",
+		IgnoreStatusOnlyUpdates: true,
+		SignificantChangesOnly:  true,
+		WatchedAnnotations:      []string{"refresh"},
+	}, cfg.Filter)
+}
@@ -15,4 +15,7 @@ const (
 	AuthHeaderCtxKey    = ContextKey(jwt.AuthHeaderCtxKey)
 	WebTokenCtxKey      = ContextKey(jwt.WebTokenCtxKey)
 	UserIDCtxKey        = ContextKey("userId")
+	ClientCtxKey        = ContextKey("client")
+	TrackerCtxKey       = ContextKey("reconcileTracker")
+	CheckpointCtxKey    = ContextKey("finalizeCheckpoint")
 )
@@ -0,0 +1,303 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/platform-mesh/golang-commons/context/keys"
+	"github.com/platform-mesh/golang-commons/logger"
+)
+
+var (
+	clientRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "platform_mesh_client_requests_total",
+		Help: "Number of kubernetes API calls made by subroutines, partitioned by subroutine, verb, group version kind and result",
+	}, []string{"subroutine", "verb", "group", "version", "kind", "result"})
+	clientRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "platform_mesh_client_request_duration_seconds",
+		Help:    "Duration of kubernetes API calls made by subroutines, partitioned by subroutine, verb and group version kind",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+	}, []string{"subroutine", "verb", "group", "version", "kind"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(clientRequests, clientRequestDuration)
+}
+
+type InstrumentationConfig struct {
+	// SlowCallThreshold is the duration after which a call is logged as slow
+	SlowCallThreshold time.Duration
+	// TracerName is the name of the tracer creating the spans of the calls
+	TracerName string
+}
+
+var defaultInstrumentationConfig = InstrumentationConfig{
+	SlowCallThreshold: time.Second,
+	TracerName:        "github.com/platform-mesh/golang-commons/controller/client",
+}
+
+func (c InstrumentationConfig) validate() error {
+	if c.SlowCallThreshold <= 0 {
+		return fmt.Errorf("the slow call threshold should be positive")
+	}
+	return nil
+}
+
+type InstrumentationOption func(*InstrumentationConfig)
+
+func WithSlowCallThreshold(d time.Duration) InstrumentationOption {
+	return func(c *InstrumentationConfig) {
+		c.SlowCallThreshold = d
+	}
+}
+
+func WithTracerName(name string) InstrumentationOption {
+	return func(c *InstrumentationConfig) {
+		c.TracerName = name
+	}
+}
+
+// Instrumentation wraps clients to create a span, record metrics and log slow calls for every API call
+type Instrumentation struct {
+	cfg InstrumentationConfig
+}
+
+func NewInstrumentation(opts ...InstrumentationOption) (*Instrumentation, error) {
+	cfg := defaultInstrumentationConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &Instrumentation{cfg: cfg}, nil
+}
+
+// Wrap returns a client instrumenting all calls, including the calls of its Status and SubResource clients,
+// labelled with the name of the subroutine
+func (i *Instrumentation) Wrap(cl client.Client, subroutine string) client.Client {
+	return &instrumentedClient{Client: cl, instrumentation: i, subroutine: subroutine}
+}
+
+// SetClientInContext stores the client in the context, e.g. to pass an instrumented client to a subroutine
+func SetClientInContext(ctx context.Context, cl client.Client) context.Context {
+	return context.WithValue(ctx, keys.ClientCtxKey, cl)
+}
+
+// LoadClientFromContext returns the client stored in the context, or the fallback if there is none
+func LoadClientFromContext(ctx context.Context, fallback client.Client) client.Client {
+	if cl, ok := ctx.Value(keys.ClientCtxKey).(client.Client); ok {
+		return cl
+	}
+	return fallback
+}
+
+type instrumentedClient struct {
+	client.Client
+	instrumentation *Instrumentation
+	subroutine      string
+}
+
+// call runs f for the verb and the object kind and instruments it
+func (c *instrumentedClient) call(ctx context.Context, verb string, gvk schema.GroupVersionKind, key client.ObjectKey, f func(ctx context.Context) error) error {
+	ctx, span := otel.Tracer(c.instrumentation.cfg.TracerName).Start(ctx, fmt.Sprintf("%s %s", verb, gvk.Kind),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("k8s.subroutine", c.subroutine),
+			attribute.String("k8s.verb", verb),
+			attribute.String("k8s.group", gvk.Group),
+			attribute.String("k8s.version", gvk.Version),
+			attribute.String("k8s.kind", gvk.Kind),
+			attribute.String("k8s.namespace", key.Namespace),
+			attribute.String("k8s.name", key.Name),
+		))
+	defer span.End()
+
+	start := time.Now()
+	err := f(ctx)
+	duration := time.Since(start)
+
+	result := resultOf(err)
+	clientRequests.WithLabelValues(c.subroutine, verb, gvk.Group, gvk.Version, gvk.Kind, result).Inc()
+	clientRequestDuration.WithLabelValues(c.subroutine, verb, gvk.Group, gvk.Version, gvk.Kind).Observe(duration.Seconds())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, result)
+	}
+
+	if duration >= c.instrumentation.cfg.SlowCallThreshold {
+		logger.LoadLoggerFromContext(ctx).Warn().
+			Str("subroutine", c.subroutine).
+			Str("verb", verb).
+			Str("gvk", gvk.String()).
+			Str("object", key.String()).
+			Dur("duration", duration).
+			Msg("slow kubernetes API call")
+	}
+	return err
+}
+
+func resultOf(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case kerrors.IsConflict(err):
+		return "conflict"
+	case kerrors.IsNotFound(err):
+		return "not_found"
+	case kerrors.IsAlreadyExists(err):
+		return "already_exists"
+	default:
+		return "error"
+	}
+}
+
+// gvkFor returns the group version kind of the object, or its go type if it is not known by the scheme
+func gvkFor(obj runtime.Object, scheme *runtime.Scheme) schema.GroupVersionKind {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return schema.GroupVersionKind{Kind: fmt.Sprintf("%T", obj)}
+	}
+	return gvk
+}
+
+func gvkForList(list client.ObjectList, scheme *runtime.Scheme) schema.GroupVersionKind {
+	gvk := gvkFor(list, scheme)
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	return gvk
+}
+
+// gvkForApply returns the group version kind of typed and unstructured apply configurations
+func gvkForApply(obj runtime.ApplyConfiguration) schema.GroupVersionKind {
+	ac, ok := obj.(interface {
+		GetAPIVersion() *string
+		GetKind() *string
+	})
+	if !ok || ac.GetAPIVersion() == nil || ac.GetKind() == nil {
+		return schema.GroupVersionKind{Kind: fmt.Sprintf("%T", obj)}
+	}
+	return schema.FromAPIVersionAndKind(*ac.GetAPIVersion(), *ac.GetKind())
+}
+
+func keyForApply(obj runtime.ApplyConfiguration) client.ObjectKey {
+	var key client.ObjectKey
+	if ac, ok := obj.(interface{ GetName() *string }); ok && ac.GetName() != nil {
+		key.Name = *ac.GetName()
+	}
+	if ac, ok := obj.(interface{ GetNamespace() *string }); ok && ac.GetNamespace() != nil {
+		key.Namespace = *ac.GetNamespace()
+	}
+	return key
+}
+
+func (c *instrumentedClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return c.call(ctx, "get", gvkFor(obj, c.Scheme()), key, func(ctx context.Context) error {
+		return c.Client.Get(ctx, key, obj, opts...)
+	})
+}
+
+func (c *instrumentedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := (&client.ListOptions{}).ApplyOptions(opts)
+	return c.call(ctx, "list", gvkForList(list, c.Scheme()), client.ObjectKey{Namespace: listOpts.Namespace}, func(ctx context.Context) error {
+		return c.Client.List(ctx, list, opts...)
+	})
+}
+
+func (c *instrumentedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return c.call(ctx, "create", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj), func(ctx context.Context) error {
+		return c.Client.Create(ctx, obj, opts...)
+	})
+}
+
+func (c *instrumentedClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return c.call(ctx, "delete", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj), func(ctx context.Context) error {
+		return c.Client.Delete(ctx, obj, opts...)
+	})
+}
+
+func (c *instrumentedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.call(ctx, "update", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj), func(ctx context.Context) error {
+		return c.Client.Update(ctx, obj, opts...)
+	})
+}
+
+func (c *instrumentedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.call(ctx, "patch", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj), func(ctx context.Context) error {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+func (c *instrumentedClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+	return c.call(ctx, "apply", gvkForApply(obj), keyForApply(obj), func(ctx context.Context) error {
+		return c.Client.Apply(ctx, obj, opts...)
+	})
+}
+
+func (c *instrumentedClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	deleteOpts := (&client.DeleteAllOfOptions{}).ApplyOptions(opts)
+	return c.call(ctx, "deletecollection", gvkFor(obj, c.Scheme()), client.ObjectKey{Namespace: deleteOpts.Namespace}, func(ctx context.Context) error {
+		return c.Client.DeleteAllOf(ctx, obj, opts...)
+	})
+}
+
+func (c *instrumentedClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *instrumentedClient) SubResource(subResource string) client.SubResourceClient {
+	return &instrumentedSubResourceClient{SubResourceClient: c.Client.SubResource(subResource), client: c, subResource: subResource}
+}
+
+type instrumentedSubResourceClient struct {
+	client.SubResourceClient
+	client      *instrumentedClient
+	subResource string
+}
+
+func (c *instrumentedSubResourceClient) verb(verb string) string {
+	return verb + "/" + c.subResource
+}
+
+func (c *instrumentedSubResourceClient) Get(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	return c.client.call(ctx, c.verb("get"), gvkFor(obj, c.client.Scheme()), client.ObjectKeyFromObject(obj), func(ctx context.Context) error {
+		return c.SubResourceClient.Get(ctx, obj, subResource, opts...)
+	})
+}
+
+func (c *instrumentedSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return c.client.call(ctx, c.verb("create"), gvkFor(obj, c.client.Scheme()), client.ObjectKeyFromObject(obj), func(ctx context.Context) error {
+		return c.SubResourceClient.Create(ctx, obj, subResource, opts...)
+	})
+}
+
+func (c *instrumentedSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return c.client.call(ctx, c.verb("update"), gvkFor(obj, c.client.Scheme()), client.ObjectKeyFromObject(obj), func(ctx context.Context) error {
+		return c.SubResourceClient.Update(ctx, obj, opts...)
+	})
+}
+
+func (c *instrumentedSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return c.client.call(ctx, c.verb("patch"), gvkFor(obj, c.client.Scheme()), client.ObjectKeyFromObject(obj), func(ctx context.Context) error {
+		return c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+	})
+}
+
+func (c *instrumentedSubResourceClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.SubResourceApplyOption) error {
+	return c.client.call(ctx, c.verb("apply"), gvkForApply(obj), keyForApply(obj), func(ctx context.Context) error {
+		return c.SubResourceClient.Apply(ctx, obj, opts...)
+	})
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func TestNewInstrumentation(t *testing.T) {
+	_, err := NewInstrumentation(WithSlowCallThreshold(0))
+	assert.Error(t, err)
+
+	i, err := NewInstrumentation(WithSlowCallThreshold(time.Minute), WithTracerName("test"))
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, i.cfg.SlowCallThreshold)
+	assert.Equal(t, "test", i.cfg.TracerName)
+}
+
+func TestInstrumentedClient(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(provider)
+
+	o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+	i, err := NewInstrumentation()
+	require.NoError(t, err)
+	cl := i.Wrap(testSupport.CreateFakeClient(t, o), "instrumented")
+	ctx := context.Background()
+
+	t.Run("records calls", func(t *testing.T) {
+		// Arrange
+		before := testutil.ToFloat64(clientRequests.WithLabelValues("instrumented", "get", "test.platform-mesh.io", "v1alpha1", "TestApiObject", "success"))
+
+		// Act
+		err := cl.Get(ctx, client.ObjectKeyFromObject(o), o)
+
+		// Assert
+		assert.NoError(t, err)
+		after := testutil.ToFloat64(clientRequests.WithLabelValues("instrumented", "get", "test.platform-mesh.io", "v1alpha1", "TestApiObject", "success"))
+		assert.Equal(t, before+1, after)
+		spans := recorder.Ended()
+		require.NotEmpty(t, spans)
+		assert.Equal(t, "get TestApiObject", spans[len(spans)-1].Name())
+	})
+
+	t.Run("records conflicts", func(t *testing.T) {
+		stale := o.DeepCopy()
+		stale.ResourceVersion = "1"
+		require.NoError(t, cl.Update(ctx, o))
+
+		err := cl.Update(ctx, stale)
+
+		assert.True(t, errors.IsConflict(err))
+		assert.Equal(t, float64(1), testutil.ToFloat64(clientRequests.WithLabelValues("instrumented", "update", "test.platform-mesh.io", "v1alpha1", "TestApiObject", "conflict")))
+	})
+
+	t.Run("records status calls", func(t *testing.T) {
+		original := o.DeepCopy()
+		o.Status.Some = "value"
+		require.NoError(t, cl.Status().Patch(ctx, o, client.MergeFrom(original)))
+
+		assert.Equal(t, float64(1), testutil.ToFloat64(clientRequests.WithLabelValues("instrumented", "patch/status", "test.platform-mesh.io", "v1alpha1", "TestApiObject", "success")))
+	})
+
+	t.Run("logs slow calls", func(t *testing.T) {
+		// Arrange
+		log := testlogger.New()
+		slow, err := NewInstrumentation(WithSlowCallThreshold(time.Nanosecond))
+		require.NoError(t, err)
+		cl := slow.Wrap(testSupport.CreateFakeClient(t, o), "slow")
+
+		// Act
+		err = cl.Get(logger.SetLoggerInContext(ctx, log.Logger), client.ObjectKeyFromObject(o), o)
+
+		// Assert
+		assert.NoError(t, err)
+		messages, err := log.GetMessagesForLevel(logger.Level(zerolog.WarnLevel))
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, "slow kubernetes API call", messages[0].Message)
+		assert.Equal(t, "slow", messages[0].Attributes["subroutine"])
+	})
+}
+
+func TestLoadClientFromContext(t *testing.T) {
+	fallback := testSupport.CreateFakeClient(t)
+	other := testSupport.CreateFakeClient(t)
+
+	assert.Equal(t, fallback, LoadClientFromContext(context.Background(), fallback))
+	assert.Equal(t, other, LoadClientFromContext(SetClientInContext(context.Background(), other), fallback))
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// WriteResult reports the outcome of a write helper
+type WriteResult struct {
+	// Written is true if the object was changed on the server
+	Written bool
+	// Attempts is the number of attempts, which is greater than one if conflicts were retried
+	Attempts int
+}
+
+type WriteConfig struct {
+	// Backoff is used to retry on conflicts
+	Backoff wait.Backoff
+	// Status writes the status subresource instead of the object
+	Status bool
+}
+
+var defaultWriteConfig = WriteConfig{
+	Backoff: defaultBackoff,
+}
+
+type WriteOption func(*WriteConfig)
+
+func WithBackoff(backoff wait.Backoff) WriteOption {
+	return func(c *WriteConfig) {
+		c.Backoff = backoff
+	}
+}
+
+func WithStatus() WriteOption {
+	return func(c *WriteConfig) {
+		c.Status = true
+	}
+}
+
+func newWriteConfig(opts ...WriteOption) WriteConfig {
+	cfg := defaultWriteConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// Mutate fetches the object, applies mutate and updates the object if anything changed. On conflicts, the object
+// is fetched and mutated again.
+func Mutate[T client.Object](ctx context.Context, cl client.Client, obj T, mutate func(T) error, opts ...WriteOption) (WriteResult, error) {
+	cfg := newWriteConfig(opts...)
+	return writeOnChange(ctx, cl, obj, mutate, cfg, func(_, obj T) error {
+		if cfg.Status {
+			return cl.Status().Update(ctx, obj)
+		}
+		return cl.Update(ctx, obj)
+	})
+}
+
+// MergePatch fetches the object, applies mutate and sends the changes as merge patch with an optimistic lock if
+// anything changed. On conflicts, the object is fetched and mutated again.
+func MergePatch[T client.Object](ctx context.Context, cl client.Client, obj T, mutate func(T) error, opts ...WriteOption) (WriteResult, error) {
+	cfg := newWriteConfig(opts...)
+	return writeOnChange(ctx, cl, obj, mutate, cfg, func(original, obj T) error {
+		patch := client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+		if cfg.Status {
+			return cl.Status().Patch(ctx, obj, patch)
+		}
+		return cl.Patch(ctx, obj, patch)
+	})
+}
+
+func writeOnChange[T client.Object](ctx context.Context, cl client.Client, obj T, mutate func(T) error, cfg WriteConfig, write func(original, obj T) error) (WriteResult, error) {
+	result := WriteResult{}
+	err := retry.RetryOnConflict(cfg.Backoff, func() error {
+		result.Attempts++
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return err
+		}
+		original := obj.DeepCopyObject().(T)
+		if err := mutate(obj); err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(original, obj) {
+			return nil
+		}
+		if err := write(original, obj); err != nil {
+			return err
+		}
+		result.Written = true
+		return nil
+	})
+	return result, err
+}
+
+// JSONPatchOperation is a single operation of a JSON patch (RFC 6902)
+type JSONPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+// TestOperation fails the JSON patch if the value at the path differs, e.g. to guard a patch against concurrent changes
+func TestOperation(path string, value any) JSONPatchOperation {
+	return JSONPatchOperation{Op: "test", Path: path, Value: value}
+}
+
+func AddOperation(path string, value any) JSONPatchOperation {
+	return JSONPatchOperation{Op: "add", Path: path, Value: value}
+}
+
+func ReplaceOperation(path string, value any) JSONPatchOperation {
+	return JSONPatchOperation{Op: "replace", Path: path, Value: value}
+}
+
+func RemoveOperation(path string) JSONPatchOperation {
+	return JSONPatchOperation{Op: "remove", Path: path}
+}
+
+// JSONPatch sends the operations as JSON patch once. The operations are not guarded by the resource version, so
+// resending them on conflicts would not help; use TestOperations to fail the patch if the object changed in between.
+// The backoff is not used.
+func JSONPatch(ctx context.Context, cl client.Client, obj client.Object, operations []JSONPatchOperation, opts ...WriteOption) (WriteResult, error) {
+	cfg := newWriteConfig(opts...)
+	result := WriteResult{}
+	if len(operations) == 0 {
+		return result, nil
+	}
+	data, err := json.Marshal(operations)
+	if err != nil {
+		return result, fmt.Errorf("failed to marshal json patch: %w", err)
+	}
+
+	resourceVersion := obj.GetResourceVersion()
+	patch := client.RawPatch(types.JSONPatchType, data)
+	result.Attempts = 1
+	if cfg.Status {
+		err = cl.Status().Patch(ctx, obj, patch)
+	} else {
+		err = cl.Patch(ctx, obj, patch)
+	}
+	result.Written = err == nil && obj.GetResourceVersion() != resourceVersion
+	return result, err
+}
+
+// ApplyStatus applies the status of the object with server-side apply as the field owner, forcing the ownership of
+// conflicting fields. The object is updated with the response. Written is only reliable if the object carries the
+// resource version it was read with.
+func ApplyStatus(ctx context.Context, cl client.Client, obj client.Object, fieldOwner string, opts ...WriteOption) (WriteResult, error) {
+	cfg := newWriteConfig(opts...)
+	result := WriteResult{}
+
+	gvk, err := apiutil.GVKForObject(obj, cl.Scheme())
+	if err != nil {
+		return result, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return result, fmt.Errorf("failed to convert object to unstructured: %w", err)
+	}
+	u := &unstructured.Unstructured{Object: map[string]any{"status": content["status"]}}
+	u.SetGroupVersionKind(gvk)
+	u.SetName(obj.GetName())
+	u.SetNamespace(obj.GetNamespace())
+
+	resourceVersion := obj.GetResourceVersion()
+	err = retry.RetryOnConflict(cfg.Backoff, func() error {
+		result.Attempts++
+		return cl.Status().Apply(ctx, client.ApplyConfigurationFromUnstructured(u), client.FieldOwner(fieldOwner), client.ForceOwnership)
+	})
+	if err != nil {
+		return result, err
+	}
+	if u.GetResourceVersion() == "" {
+		// not every client returns the applied object, e.g. the fake client
+		if err := cl.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			return result, err
+		}
+	} else if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return result, fmt.Errorf("failed to convert applied object: %w", err)
+	}
+	result.Written = obj.GetResourceVersion() != resourceVersion
+	return result, nil
+}
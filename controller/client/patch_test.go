@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/platform-mesh/golang-commons/controller/testSupport"
+)
+
+var testBackoff = wait.Backoff{Duration: time.Millisecond, Steps: 3}
+
+// conflictingClient returns a conflict for the first n writes
+func conflictingClient(t *testing.T, o client.Object, n int) client.Client {
+	conflict := kerrors.NewConflict(schema.GroupResource{Resource: "testapiobjects"}, o.GetName(), errors.New("conflict"))
+	writes := 0
+	return interceptor.NewClient(testSupport.CreateFakeClient(t, o).(client.WithWatch), interceptor.Funcs{
+		Update: func(ctx context.Context, cl client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if writes++; writes <= n {
+				return conflict
+			}
+			return cl.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, cl client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if writes++; writes <= n {
+				return conflict
+			}
+			return cl.Patch(ctx, obj, patch, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, cl client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			if writes++; writes <= n {
+				return conflict
+			}
+			return cl.SubResource(subResource).Update(ctx, obj, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, cl client.Client, subResource string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			if writes++; writes <= n {
+				return conflict
+			}
+			return cl.SubResource(subResource).Patch(ctx, obj, patch, opts...)
+		},
+	})
+}
+
+func TestMutate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("updates changed objects", func(t *testing.T) {
+		// Arrange
+		o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+		cl := testSupport.CreateFakeClient(t, o)
+
+		// Act
+		result, err := Mutate(ctx, cl, o, func(obj *testSupport.TestApiObject) error {
+			obj.Labels = map[string]string{"a": "b"}
+			return nil
+		})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, WriteResult{Written: true, Attempts: 1}, result)
+		persisted := &testSupport.TestApiObject{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(o), persisted))
+		assert.Equal(t, "b", persisted.Labels["a"])
+	})
+
+	t.Run("skips unchanged objects", func(t *testing.T) {
+		o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+		cl := conflictingClient(t, o, 10)
+
+		result, err := Mutate(ctx, cl, o, func(obj *testSupport.TestApiObject) error { return nil })
+
+		require.NoError(t, err)
+		assert.False(t, result.Written)
+	})
+
+	t.Run("retries conflicts", func(t *testing.T) {
+		o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+		cl := conflictingClient(t, o, 2)
+
+		result, err := Mutate(ctx, cl, o, func(obj *testSupport.TestApiObject) error {
+			obj.Status.Some = "value"
+			return nil
+		}, WithBackoff(testBackoff), WithStatus())
+
+		require.NoError(t, err)
+		assert.Equal(t, WriteResult{Written: true, Attempts: 3}, result)
+	})
+
+	t.Run("gives up after the backoff", func(t *testing.T) {
+		o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+		cl := conflictingClient(t, o, 10)
+
+		result, err := Mutate(ctx, cl, o, func(obj *testSupport.TestApiObject) error {
+			obj.Labels = map[string]string{"a": "b"}
+			return nil
+		}, WithBackoff(testBackoff))
+
+		assert.True(t, kerrors.IsConflict(err))
+		assert.Equal(t, WriteResult{Written: false, Attempts: 3}, result)
+	})
+
+	t.Run("returns mutate errors", func(t *testing.T) {
+		o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+		cl := testSupport.CreateFakeClient(t, o)
+
+		_, err := Mutate(ctx, cl, o, func(obj *testSupport.TestApiObject) error { return errors.New("failed") })
+
+		assert.EqualError(t, err, "failed")
+	})
+}
+
+func TestMergePatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("patches the status with an optimistic lock", func(t *testing.T) {
+		// Arrange
+		o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+		cl := conflictingClient(t, o, 1)
+
+		// Act
+		result, err := MergePatch(ctx, cl, o, func(obj *testSupport.TestApiObject) error {
+			obj.Status.Some = "value"
+			return nil
+		}, WithBackoff(testBackoff), WithStatus())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, WriteResult{Written: true, Attempts: 2}, result)
+		persisted := &testSupport.TestApiObject{}
+		require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(o), persisted))
+		assert.Equal(t, "value", persisted.Status.Some)
+	})
+
+	t.Run("skips unchanged objects", func(t *testing.T) {
+		o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+		cl := testSupport.CreateFakeClient(t, o)
+
+		result, err := MergePatch(ctx, cl, o, func(obj *testSupport.TestApiObject) error { return nil })
+
+		require.NoError(t, err)
+		assert.Equal(t, WriteResult{Written: false, Attempts: 1}, result)
+	})
+}
+
+func TestJSONPatch(t *testing.T) {
+	ctx := context.Background()
+	o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test", Labels: map[string]string{"a": "b"}}}
+	cl := testSupport.CreateFakeClient(t, o)
+
+	t.Run("applies the operations if the tests pass", func(t *testing.T) {
+		result, err := JSONPatch(ctx, cl, o, []JSONPatchOperation{
+			TestOperation("/metadata/labels/a", "b"),
+			ReplaceOperation("/metadata/labels/a", "c"),
+			AddOperation("/metadata/labels/d", "e"),
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.Written)
+		assert.Equal(t, map[string]string{"a": "c", "d": "e"}, o.Labels)
+	})
+
+	t.Run("fails if a test fails", func(t *testing.T) {
+		result, err := JSONPatch(ctx, cl, o, []JSONPatchOperation{
+			TestOperation("/metadata/labels/a", "b"),
+			RemoveOperation("/metadata/labels/a"),
+		})
+
+		assert.Error(t, err)
+		assert.False(t, result.Written)
+		assert.Equal(t, 1, result.Attempts)
+	})
+
+	t.Run("does not resend the operations on conflicts", func(t *testing.T) {
+		conflicting := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+
+		result, err := JSONPatch(ctx, conflictingClient(t, conflicting, 1), conflicting, []JSONPatchOperation{
+			AddOperation("/metadata/labels", map[string]string{"a": "b"}),
+		}, WithBackoff(testBackoff))
+
+		assert.True(t, kerrors.IsConflict(err))
+		assert.Equal(t, WriteResult{Written: false, Attempts: 1}, result)
+	})
+
+	t.Run("does nothing without operations", func(t *testing.T) {
+		result, err := JSONPatch(ctx, cl, o, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, WriteResult{}, result)
+	})
+}
+
+func TestApplyStatus(t *testing.T) {
+	ctx := context.Background()
+	o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+	cl := testSupport.CreateFakeClient(t, o)
+
+	o.Status.Some = "value"
+	result, err := ApplyStatus(ctx, cl, o, "test-owner")
+
+	require.NoError(t, err)
+	assert.True(t, result.Written)
+	persisted := &testSupport.TestApiObject{}
+	require.NoError(t, cl.Get(ctx, client.ObjectKeyFromObject(o), persisted))
+	assert.Equal(t, "value", persisted.Status.Some)
+}
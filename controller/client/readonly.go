@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type ReadOnlyMode int
+
+const (
+	// RejectWrites rejects all mutating calls with a ReadOnlyViolationError
+	RejectWrites ReadOnlyMode = iota
+	// DryRunWrites sends all mutating calls as dry run, so that they are validated but not persisted
+	DryRunWrites
+)
+
+// ReadOnlyViolationError is returned for mutating calls of a read-only client
+type ReadOnlyViolationError struct {
+	Subroutine string
+	Verb       string
+	GVK        schema.GroupVersionKind
+	Key        client.ObjectKey
+}
+
+func (e *ReadOnlyViolationError) Error() string {
+	return fmt.Sprintf("subroutine %q attempted to %s %s %s in read-only mode", e.Subroutine, e.Verb, e.GVK.String(), e.Key.String())
+}
+
+// IsReadOnlyViolation returns whether the error is caused by a mutating call of a read-only client
+func IsReadOnlyViolation(err error) bool {
+	var violation *ReadOnlyViolationError
+	return errors.As(err, &violation)
+}
+
+// NewReadOnlyClient returns a client guarding all mutating calls, including the calls of its Status and SubResource
+// clients. Depending on the mode, writes are rejected with an error naming the subroutine or sent as dry run.
+func NewReadOnlyClient(cl client.Client, subroutine string, mode ReadOnlyMode) client.Client {
+	return &readOnlyClient{Client: cl, subroutine: subroutine, mode: mode}
+}
+
+type readOnlyClient struct {
+	client.Client
+	subroutine string
+	mode       ReadOnlyMode
+}
+
+// guard returns the error rejecting the write, or nil if it should be sent as dry run
+func (c *readOnlyClient) guard(verb string, gvk schema.GroupVersionKind, key client.ObjectKey) error {
+	if c.mode == DryRunWrites {
+		return nil
+	}
+	return &ReadOnlyViolationError{Subroutine: c.subroutine, Verb: verb, GVK: gvk, Key: key}
+}
+
+func (c *readOnlyClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.guard("create", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *readOnlyClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := c.guard("delete", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.Client.Delete(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *readOnlyClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.guard("update", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *readOnlyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.guard("patch", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
+
+func (c *readOnlyClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+	if err := c.guard("apply", gvkForApply(obj), keyForApply(obj)); err != nil {
+		return err
+	}
+	return c.Client.Apply(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *readOnlyClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	deleteOpts := (&client.DeleteAllOfOptions{}).ApplyOptions(opts)
+	if err := c.guard("deletecollection", gvkFor(obj, c.Scheme()), client.ObjectKey{Namespace: deleteOpts.Namespace}); err != nil {
+		return err
+	}
+	return c.Client.DeleteAllOf(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *readOnlyClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *readOnlyClient) SubResource(subResource string) client.SubResourceClient {
+	return &readOnlySubResourceClient{SubResourceClient: c.Client.SubResource(subResource), client: c, subResource: subResource}
+}
+
+type readOnlySubResourceClient struct {
+	client.SubResourceClient
+	client      *readOnlyClient
+	subResource string
+}
+
+func (c *readOnlySubResourceClient) verb(verb string) string {
+	return verb + "/" + c.subResource
+}
+
+func (c *readOnlySubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	if err := c.client.guard(c.verb("create"), gvkFor(obj, c.client.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Create(ctx, obj, subResource, append(opts, client.DryRunAll)...)
+}
+
+func (c *readOnlySubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if err := c.client.guard(c.verb("update"), gvkFor(obj, c.client.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Update(ctx, obj, append(opts, client.DryRunAll)...)
+}
+
+func (c *readOnlySubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if err := c.client.guard(c.verb("patch"), gvkFor(obj, c.client.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+}
+
+func (c *readOnlySubResourceClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.SubResourceApplyOption) error {
+	if err := c.client.guard(c.verb("apply"), gvkForApply(obj), keyForApply(obj)); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Apply(ctx, obj, append(opts, client.DryRunAll)...)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/platform-mesh/golang-commons/controller/testSupport"
+)
+
+func TestReadOnlyClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rejects writes", func(t *testing.T) {
+		// Arrange
+		o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+		cl := NewReadOnlyClient(testSupport.CreateFakeClient(t, o), "mySubroutine", RejectWrites)
+		created := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "created", Namespace: "test"}}
+
+		// Act
+		createErr := cl.Create(ctx, created)
+		statusErr := cl.Status().Update(ctx, o)
+		deleteErr := cl.Delete(ctx, o)
+		getErr := cl.Get(ctx, client.ObjectKeyFromObject(o), o)
+
+		// Assert
+		assert.True(t, IsReadOnlyViolation(createErr))
+		assert.EqualError(t, createErr, `subroutine "mySubroutine" attempted to create test.platform-mesh.io/v1alpha1, Kind=TestApiObject test/created in read-only mode`)
+		assert.True(t, IsReadOnlyViolation(fmt.Errorf("wrapped: %w", statusErr)))
+		assert.Contains(t, statusErr.Error(), "update/status")
+		assert.True(t, IsReadOnlyViolation(deleteErr))
+		assert.NoError(t, getErr)
+	})
+
+	t.Run("sends writes as dry run", func(t *testing.T) {
+		// Arrange
+		o := &testSupport.TestApiObject{ObjectMeta: v1.ObjectMeta{Name: "test", Namespace: "test"}}
+		underlying := testSupport.CreateFakeClient(t, o)
+		cl := NewReadOnlyClient(underlying, "mySubroutine", DryRunWrites)
+
+		// Act
+		original := o.DeepCopy()
+		o.Status.Some = "changed"
+		err := cl.Status().Patch(ctx, o, client.MergeFrom(original))
+		require.NoError(t, err)
+		err = cl.Delete(ctx, o)
+		require.NoError(t, err)
+
+		// Assert
+		persisted := &testSupport.TestApiObject{}
+		require.NoError(t, underlying.Get(ctx, client.ObjectKeyFromObject(o), persisted))
+		assert.Empty(t, persisted.Status.Some)
+	})
+
+	t.Run("is not a violation for other errors", func(t *testing.T) {
+		assert.False(t, IsReadOnlyViolation(fmt.Errorf("other")))
+	})
+}
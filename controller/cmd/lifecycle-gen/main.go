@@ -0,0 +1,258 @@
+// lifecycle-gen generates the accessor methods required by the lifecycle interfaces for CRDs embedding
+// api.CommonStatus into their status. Mark the CRD type and run the generator in its package:
+//
+//	//go:generate go run github.com/platform-mesh/golang-commons/controller/cmd/lifecycle-gen
+//
+//	// +lifecycle:commonstatus
+//	type Account struct {
+//		metav1.TypeMeta   `json:",inline"`
+//		metav1.ObjectMeta `json:"metadata,omitempty"`
+//		Status AccountStatus `json:"status,omitempty"`
+//	}
+//
+// The marker accepts the name of the status field, e.g. +lifecycle:commonstatus:field=State, which defaults to Status.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	marker       = "+lifecycle:commonstatus"
+	commonStatus = "CommonStatus"
+	apiPackage   = "github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the package containing the marked types")
+	output := flag.String("output", "zz_generated.lifecycle.go", "name of the generated file in the directory")
+	flag.Parse()
+
+	code, err := generate(*dir, *output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lifecycle-gen: %v\n", err)
+		os.Exit(1)
+	}
+	if code == nil {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(*dir, *output), code, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "lifecycle-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type markedType struct {
+	Name        string
+	StatusField string
+}
+
+// generate returns the generated code for the marked types of the package in dir, or nil if no type is marked
+func generate(dir, output string) ([]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var pkgName string
+	structs := map[string]*ast.StructType{}
+	var marked []markedType
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if pkgName != "" && pkgName != file.Name.Name {
+			return nil, fmt.Errorf("expected a single package in %s, found %s and %s", dir, pkgName, file.Name.Name)
+		}
+		pkgName = file.Name.Name
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if s, ok := typeSpec.Type.(*ast.StructType); ok {
+					structs[typeSpec.Name.Name] = s
+				}
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+				if field, ok := parseMarker(doc); ok {
+					marked = append(marked, markedType{Name: typeSpec.Name.Name, StatusField: field})
+				}
+			}
+		}
+	}
+	if len(marked) == 0 {
+		return nil, nil
+	}
+	sort.Slice(marked, func(i, j int) bool { return marked[i].Name < marked[j].Name })
+
+	for _, t := range marked {
+		if err := validate(t, structs); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, struct {
+		Package    string
+		APIPackage string
+		Types      []markedType
+	}{Package: pkgName, APIPackage: apiPackage, Types: marked}); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// parseMarker returns the status field of the marker in the comments and whether the marker is present
+func parseMarker(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if text == marker {
+			return "Status", true
+		}
+		if field, ok := strings.CutPrefix(text, marker+":field="); ok && field != "" {
+			return field, true
+		}
+	}
+	return "", false
+}
+
+// validate checks that the status field of the marked type embeds CommonStatus
+func validate(t markedType, structs map[string]*ast.StructType) error {
+	s, ok := structs[t.Name]
+	if !ok {
+		return fmt.Errorf("marked type %s is not a struct", t.Name)
+	}
+	statusType := ""
+	for _, field := range s.Fields.List {
+		for _, name := range field.Names {
+			if name.Name != t.StatusField {
+				continue
+			}
+			if ident, ok := field.Type.(*ast.Ident); ok {
+				statusType = ident.Name
+			}
+		}
+	}
+	if statusType == "" {
+		return fmt.Errorf("type %s has no field %s of a struct type of the package", t.Name, t.StatusField)
+	}
+	status, ok := structs[statusType]
+	if !ok {
+		return fmt.Errorf("status field %s.%s is not a struct", t.Name, t.StatusField)
+	}
+	for _, field := range status.Fields.List {
+		if len(field.Names) != 0 {
+			continue
+		}
+		switch typ := field.Type.(type) {
+		case *ast.SelectorExpr:
+			if typ.Sel.Name == commonStatus {
+				return nil
+			}
+		case *ast.Ident:
+			if typ.Name == commonStatus {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("status type %s of %s does not embed %s", statusType, t.Name, commonStatus)
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by lifecycle-gen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	lifecycleapi "{{ .APIPackage }}"
+)
+{{ range .Types }}
+func (in *{{ .Name }}) GetConditions() []metav1.Condition {
+	return in.{{ .StatusField }}.CommonStatus.Conditions
+}
+
+func (in *{{ .Name }}) SetConditions(conditions []metav1.Condition) {
+	in.{{ .StatusField }}.CommonStatus.Conditions = conditions
+}
+
+func (in *{{ .Name }}) GetObservedGeneration() int64 {
+	return in.{{ .StatusField }}.CommonStatus.ObservedGeneration
+}
+
+func (in *{{ .Name }}) SetObservedGeneration(generation int64) {
+	in.{{ .StatusField }}.CommonStatus.ObservedGeneration = generation
+}
+
+func (in *{{ .Name }}) GetNextReconcileTime() metav1.Time {
+	return in.{{ .StatusField }}.CommonStatus.NextReconcileTime
+}
+
+func (in *{{ .Name }}) SetNextReconcileTime(time metav1.Time) {
+	in.{{ .StatusField }}.CommonStatus.NextReconcileTime = time
+}
+
+func (in *{{ .Name }}) GetSubroutineReports() []lifecycleapi.SubroutineReport {
+	return in.{{ .StatusField }}.CommonStatus.SubroutineReports
+}
+
+func (in *{{ .Name }}) SetSubroutineReports(reports []lifecycleapi.SubroutineReport) {
+	in.{{ .StatusField }}.CommonStatus.SubroutineReports = reports
+}
+
+func (in *{{ .Name }}) GetSubroutineInputHashes() []lifecycleapi.SubroutineInputHash {
+	return in.{{ .StatusField }}.CommonStatus.InputHashes
+}
+
+func (in *{{ .Name }}) SetSubroutineInputHashes(hashes []lifecycleapi.SubroutineInputHash) {
+	in.{{ .StatusField }}.CommonStatus.InputHashes = hashes
+}
+
+func (in *{{ .Name }}) GetFinalizeCheckpoints() []lifecycleapi.SubroutineFinalizeCheckpoint {
+	return in.{{ .StatusField }}.CommonStatus.FinalizeCheckpoints
+}
+
+func (in *{{ .Name }}) SetFinalizeCheckpoints(checkpoints []lifecycleapi.SubroutineFinalizeCheckpoint) {
+	in.{{ .StatusField }}.CommonStatus.FinalizeCheckpoints = checkpoints
+}
+
+func (in *{{ .Name }}) GetTerminators() []string {
+	return in.{{ .StatusField }}.CommonStatus.Terminators
+}
+
+func (in *{{ .Name }}) SetTerminators(terminators []string) {
+	in.{{ .StatusField }}.CommonStatus.Terminators = terminators
+}
+
+func (in *{{ .Name }}) GetInitializers() []string {
+	return in.{{ .StatusField }}.CommonStatus.Initializers
+}
+
+func (in *{{ .Name }}) SetInitializers(initializers []string) {
+	in.{{ .StatusField }}.CommonStatus.Initializers = initializers
+}
+{{ end }}`))
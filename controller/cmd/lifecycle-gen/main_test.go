@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePackage(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return dir
+}
+
+const statusTypes = `package v1alpha1
+
+import "github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+
+type AccountStatus struct {
+	api.CommonStatus ` + "`json:\",inline\"`" + `
+}
+`
+
+func TestGenerate(t *testing.T) {
+	t.Run("generates accessors for marked types", func(t *testing.T) {
+		// Arrange
+		dir := writePackage(t, map[string]string{
+			"status.go": statusTypes,
+			"types.go": `package v1alpha1
+
+// Account is a test type
+// +lifecycle:commonstatus
+type Account struct {
+	Status AccountStatus
+}
+
+// +lifecycle:commonstatus:field=State
+type Workspace struct {
+	State AccountStatus
+}
+
+type Unmarked struct {
+	Status AccountStatus
+}
+`,
+		})
+
+		// Act
+		code, err := generate(dir, "zz_generated.lifecycle.go")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Contains(t, string(code), "// Code generated by lifecycle-gen. DO NOT EDIT.\n\npackage v1alpha1")
+		assert.Contains(t, string(code), "func (in *Account) GetConditions() []metav1.Condition {\n\treturn in.Status.CommonStatus.Conditions")
+		assert.Contains(t, string(code), "func (in *Workspace) SetObservedGeneration(generation int64) {\n\tin.State.CommonStatus.ObservedGeneration = generation")
+		assert.Contains(t, string(code), "func (in *Account) SetTerminators(terminators []string) {\n\tin.Status.CommonStatus.Terminators = terminators")
+		assert.Contains(t, string(code), "func (in *Account) GetFinalizeCheckpoints() []lifecycleapi.SubroutineFinalizeCheckpoint {\n\treturn in.Status.CommonStatus.FinalizeCheckpoints")
+		assert.NotContains(t, string(code), "Unmarked")
+	})
+
+	t.Run("ignores the previously generated file", func(t *testing.T) {
+		dir := writePackage(t, map[string]string{
+			"status.go":                 statusTypes,
+			"zz_generated.lifecycle.go": "package v1alpha1\n\n// +lifecycle:commonstatus\ntype Stale struct{}\n",
+		})
+
+		code, err := generate(dir, "zz_generated.lifecycle.go")
+
+		require.NoError(t, err)
+		assert.Nil(t, code)
+	})
+
+	t.Run("fails if the status does not embed CommonStatus", func(t *testing.T) {
+		dir := writePackage(t, map[string]string{
+			"types.go": `package v1alpha1
+
+// +lifecycle:commonstatus
+type Account struct {
+	Status AccountStatus
+}
+
+type AccountStatus struct {
+	Conditions []string
+}
+`,
+		})
+
+		_, err := generate(dir, "zz_generated.lifecycle.go")
+
+		assert.EqualError(t, err, "status type AccountStatus of Account does not embed CommonStatus")
+	})
+
+	t.Run("fails if the status field is missing", func(t *testing.T) {
+		dir := writePackage(t, map[string]string{
+			"status.go": statusTypes,
+			"types.go":  "package v1alpha1\n\n// +lifecycle:commonstatus:field=State\ntype Account struct {\n\tStatus AccountStatus\n}\n",
+		})
+
+		_, err := generate(dir, "zz_generated.lifecycle.go")
+
+		assert.EqualError(t, err, "type Account has no field State of a struct type of the package")
+	})
+}
@@ -7,12 +7,29 @@ type ConditionsService interface {
 	ConditionsGetter
 }
 
+// ConditionsSetter sets conditions with the generation of the object as observed generation. The last transition
+// time is only changed if the status changes. All setters return whether the conditions were changed.
 type ConditionsSetter interface {
-	SetTrue(objectMeta metav1.ObjectMeta, conditions *[]metav1.Condition, reason, message string)
-	SetFalse(objectMeta metav1.ObjectMeta, conditions *[]metav1.Condition, reason, message string)
+	SetTrue(obj metav1.Object, conditions *[]metav1.Condition, reason, message string) bool
+	SetFalse(obj metav1.Object, conditions *[]metav1.Condition, reason, message string) bool
+	SetUnknown(obj metav1.Object, conditions *[]metav1.Condition, reason, message string) bool
+	// Set sets a condition of any type, e.g. to handle several condition types with one service
+	Set(obj metav1.Object, conditions *[]metav1.Condition, conditionType string, status metav1.ConditionStatus, reason, message string) bool
+	// SetSummary sets the condition to False if any of the condition types is False, to Unknown if any is Unknown,
+	// missing or outdated, and to True otherwise
+	SetSummary(obj metav1.Object, conditions *[]metav1.Condition, conditionTypes ...string) bool
+	// Mirror sets the condition to the status, reason and message of a condition of another object, e.g. of a
+	// dependency, or to Unknown if the other object does not have the condition
+	Mirror(obj metav1.Object, conditions *[]metav1.Condition, source []metav1.Condition, sourceType string) bool
 }
 
 type ConditionsGetter interface {
 	GetStatus(conditions []metav1.Condition) *metav1.Condition
 	IsStatusTrue(conditions []metav1.Condition) bool
+	IsStatusUnknown(conditions []metav1.Condition) bool
+	// Get returns the condition of any type
+	Get(conditions []metav1.Condition, conditionType string) *metav1.Condition
+	// IsUpToDate returns whether the conditions of the given types, or the condition of the service if no types are
+	// given, exist and were observed for the current generation of the object
+	IsUpToDate(obj metav1.Object, conditions []metav1.Condition, conditionTypes ...string) bool
 }
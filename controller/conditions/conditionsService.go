@@ -1,12 +1,16 @@
 package conditions
 
 import (
-	"time"
-
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	ReasonReady    = "Ready"
+	ReasonMissing  = "Missing"
+	ReasonOutdated = "Outdated"
+)
+
 type conditionsService struct {
 	conditionType string
 }
@@ -16,24 +20,67 @@ func NewConditionsService(conditionType string) ConditionsService {
 }
 
 // Functions
-func setCondition(objectMeta metav1.ObjectMeta, conditions *[]metav1.Condition, conditionType string, status metav1.ConditionStatus, reason, message string) {
+func setCondition(obj metav1.Object, conditions *[]metav1.Condition, conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	// The last transition time is set by meta.SetStatusCondition if the status changes
 	workflowCondition := metav1.Condition{
 		Type:               conditionType,
 		Status:             status,
-		ObservedGeneration: objectMeta.GetGeneration(),
-		LastTransitionTime: metav1.Time{Time: time.Now()},
+		ObservedGeneration: obj.GetGeneration(),
 		Reason:             reason,
 		Message:            message,
 	}
-	meta.SetStatusCondition(conditions, workflowCondition)
+	return meta.SetStatusCondition(conditions, workflowCondition)
+}
+
+func (c *conditionsService) SetTrue(obj metav1.Object, conditions *[]metav1.Condition, reason, message string) bool {
+	return setCondition(obj, conditions, c.conditionType, metav1.ConditionTrue, reason, message)
+}
+
+func (c *conditionsService) SetFalse(obj metav1.Object, conditions *[]metav1.Condition, reason, message string) bool {
+	return setCondition(obj, conditions, c.conditionType, metav1.ConditionFalse, reason, message)
+}
+
+func (c *conditionsService) SetUnknown(obj metav1.Object, conditions *[]metav1.Condition, reason, message string) bool {
+	return setCondition(obj, conditions, c.conditionType, metav1.ConditionUnknown, reason, message)
 }
 
-func (c *conditionsService) SetTrue(objectMeta metav1.ObjectMeta, conditions *[]metav1.Condition, reason, message string) {
-	setCondition(objectMeta, conditions, c.conditionType, metav1.ConditionTrue, reason, message)
+func (c *conditionsService) Set(obj metav1.Object, conditions *[]metav1.Condition, conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	return setCondition(obj, conditions, conditionType, status, reason, message)
 }
 
-func (c *conditionsService) SetFalse(objectMeta metav1.ObjectMeta, conditions *[]metav1.Condition, reason, message string) {
-	setCondition(objectMeta, conditions, c.conditionType, metav1.ConditionFalse, reason, message)
+func (c *conditionsService) SetSummary(obj metav1.Object, conditions *[]metav1.Condition, conditionTypes ...string) bool {
+	var unknown *metav1.Condition
+	for _, conditionType := range conditionTypes {
+		cond := meta.FindStatusCondition(*conditions, conditionType)
+		switch {
+		case cond == nil:
+			if unknown == nil {
+				unknown = &metav1.Condition{Reason: ReasonMissing, Message: "condition " + conditionType + " is missing"}
+			}
+		case cond.Status == metav1.ConditionFalse:
+			return c.SetFalse(obj, conditions, cond.Reason, cond.Message)
+		case cond.ObservedGeneration != obj.GetGeneration():
+			if unknown == nil {
+				unknown = &metav1.Condition{Reason: ReasonOutdated, Message: "condition " + conditionType + " is outdated"}
+			}
+		case cond.Status != metav1.ConditionTrue:
+			if unknown == nil {
+				unknown = cond
+			}
+		}
+	}
+	if unknown != nil {
+		return c.SetUnknown(obj, conditions, unknown.Reason, unknown.Message)
+	}
+	return c.SetTrue(obj, conditions, ReasonReady, "")
+}
+
+func (c *conditionsService) Mirror(obj metav1.Object, conditions *[]metav1.Condition, source []metav1.Condition, sourceType string) bool {
+	cond := meta.FindStatusCondition(source, sourceType)
+	if cond == nil {
+		return c.SetUnknown(obj, conditions, ReasonMissing, "condition "+sourceType+" is missing")
+	}
+	return setCondition(obj, conditions, c.conditionType, cond.Status, cond.Reason, cond.Message)
 }
 
 func (c *conditionsService) GetStatus(conditions []metav1.Condition) *metav1.Condition {
@@ -47,3 +94,25 @@ func (c *conditionsService) IsStatusTrue(conditions []metav1.Condition) bool {
 	}
 	return false
 }
+
+func (c *conditionsService) IsStatusUnknown(conditions []metav1.Condition) bool {
+	cond := c.GetStatus(conditions)
+	return cond == nil || cond.Status == metav1.ConditionUnknown
+}
+
+func (c *conditionsService) Get(conditions []metav1.Condition, conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(conditions, conditionType)
+}
+
+func (c *conditionsService) IsUpToDate(obj metav1.Object, conditions []metav1.Condition, conditionTypes ...string) bool {
+	if len(conditionTypes) == 0 {
+		conditionTypes = []string{c.conditionType}
+	}
+	for _, conditionType := range conditionTypes {
+		cond := meta.FindStatusCondition(conditions, conditionType)
+		if cond == nil || cond.ObservedGeneration != obj.GetGeneration() {
+			return false
+		}
+	}
+	return true
+}
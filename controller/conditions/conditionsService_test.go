@@ -2,6 +2,7 @@ package conditions
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,7 +24,7 @@ func TestConditionsService(t *testing.T) {
 		setter := NewConditionsService("MyConditionType")
 
 		// Act
-		setter.SetTrue(metav1.ObjectMeta{}, &conditions, "reason", "message")
+		setter.SetTrue(&metav1.ObjectMeta{}, &conditions, "reason", "message")
 
 		// Assert
 		assert.Equal(t, len(conditions), 1)
@@ -39,7 +40,7 @@ func TestConditionsService(t *testing.T) {
 		setter := NewConditionsService("MyConditionType")
 
 		// Act
-		setter.SetFalse(metav1.ObjectMeta{}, &conditions, "reason", "message")
+		setter.SetFalse(&metav1.ObjectMeta{}, &conditions, "reason", "message")
 
 		// Assert
 		assert.Equal(t, len(conditions), 1)
@@ -55,7 +56,7 @@ func TestConditionsService(t *testing.T) {
 		setter := NewConditionsService("MyConditionType")
 
 		// Act
-		setter.SetFalse(metav1.ObjectMeta{}, &conditions, "reason", "message")
+		setter.SetFalse(&metav1.ObjectMeta{}, &conditions, "reason", "message")
 		val := setter.GetStatus(conditions)
 
 		// Assert
@@ -88,7 +89,7 @@ func TestConditionsService(t *testing.T) {
 		setter := NewConditionsService("MyConditionType")
 
 		// Act
-		setter.SetTrue(metav1.ObjectMeta{}, &conditions, "reason", "message")
+		setter.SetTrue(&metav1.ObjectMeta{}, &conditions, "reason", "message")
 		val := setter.GetStatus(conditions)
 
 		// Assert
@@ -100,7 +101,7 @@ func TestConditionsService(t *testing.T) {
 		setter := NewConditionsService("MyConditionType")
 
 		// Act
-		setter.SetTrue(metav1.ObjectMeta{}, &conditions, "reason", "message")
+		setter.SetTrue(&metav1.ObjectMeta{}, &conditions, "reason", "message")
 		val := setter.IsStatusTrue(conditions)
 
 		// Assert
@@ -112,7 +113,7 @@ func TestConditionsService(t *testing.T) {
 		setter := NewConditionsService("MyConditionType")
 
 		// Act
-		setter.SetFalse(metav1.ObjectMeta{}, &conditions, "reason", "message")
+		setter.SetFalse(&metav1.ObjectMeta{}, &conditions, "reason", "message")
 		val := setter.IsStatusTrue(conditions)
 
 		// Assert
@@ -131,3 +132,129 @@ func TestConditionsService(t *testing.T) {
 		assert.False(t, val)
 	})
 }
+
+func TestConditionsServiceTransitions(t *testing.T) {
+	obj := &metav1.ObjectMeta{Generation: 2}
+	service := NewConditionsService("Ready")
+
+	t.Run("Set to Unknown", func(t *testing.T) {
+		// Arrange
+		conditions := []metav1.Condition{}
+
+		// Act
+		changed := service.SetUnknown(obj, &conditions, "reason", "message")
+
+		// Assert
+		assert.True(t, changed)
+		assert.True(t, service.IsStatusUnknown(conditions))
+		assert.Equal(t, int64(2), conditions[0].ObservedGeneration)
+	})
+
+	t.Run("Preserves the transition time if the status does not change", func(t *testing.T) {
+		// Arrange
+		transition := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		conditions := []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "old", LastTransitionTime: transition}}
+
+		// Act
+		changed := service.SetTrue(obj, &conditions, "reason", "message")
+
+		// Assert
+		assert.True(t, changed)
+		assert.Equal(t, transition, conditions[0].LastTransitionTime)
+		assert.Equal(t, "reason", conditions[0].Reason)
+		assert.False(t, service.SetTrue(obj, &conditions, "reason", "message"))
+
+		// Act
+		service.SetFalse(obj, &conditions, "reason", "message")
+
+		// Assert
+		assert.True(t, conditions[0].LastTransitionTime.After(transition.Time))
+	})
+
+	t.Run("Handles several condition types", func(t *testing.T) {
+		// Arrange
+		conditions := []metav1.Condition{}
+
+		// Act
+		service.SetTrue(obj, &conditions, "reason", "message")
+		service.Set(obj, &conditions, "Synced", metav1.ConditionFalse, "reason", "message")
+
+		// Assert
+		assert.Len(t, conditions, 2)
+		assert.True(t, service.IsStatusTrue(conditions))
+		assert.Equal(t, metav1.ConditionFalse, service.Get(conditions, "Synced").Status)
+	})
+
+	t.Run("IsUpToDate", func(t *testing.T) {
+		// Arrange
+		conditions := []metav1.Condition{
+			{Type: "Ready", Status: metav1.ConditionTrue, ObservedGeneration: 2},
+			{Type: "Synced", Status: metav1.ConditionTrue, ObservedGeneration: 1},
+		}
+
+		// Act & Assert
+		assert.True(t, service.IsUpToDate(obj, conditions))
+		assert.False(t, service.IsUpToDate(obj, conditions, "Ready", "Synced"))
+		assert.False(t, service.IsUpToDate(obj, conditions, "Missing"))
+	})
+
+	t.Run("SetSummary", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			conditions []metav1.Condition
+			status     metav1.ConditionStatus
+			reason     string
+		}{
+			{name: "all true", status: metav1.ConditionTrue, reason: ReasonReady, conditions: []metav1.Condition{
+				{Type: "A", Status: metav1.ConditionTrue, ObservedGeneration: 2},
+				{Type: "B", Status: metav1.ConditionTrue, ObservedGeneration: 2},
+			}},
+			{name: "one false", status: metav1.ConditionFalse, reason: "Failed", conditions: []metav1.Condition{
+				{Type: "A", Status: metav1.ConditionUnknown, ObservedGeneration: 2, Reason: "Pending"},
+				{Type: "B", Status: metav1.ConditionFalse, ObservedGeneration: 2, Reason: "Failed"},
+			}},
+			{name: "one unknown", status: metav1.ConditionUnknown, reason: "Pending", conditions: []metav1.Condition{
+				{Type: "A", Status: metav1.ConditionUnknown, ObservedGeneration: 2, Reason: "Pending"},
+				{Type: "B", Status: metav1.ConditionTrue, ObservedGeneration: 2},
+			}},
+			{name: "one outdated", status: metav1.ConditionUnknown, reason: ReasonOutdated, conditions: []metav1.Condition{
+				{Type: "A", Status: metav1.ConditionTrue, ObservedGeneration: 1},
+				{Type: "B", Status: metav1.ConditionTrue, ObservedGeneration: 2},
+			}},
+			{name: "one missing", status: metav1.ConditionUnknown, reason: ReasonMissing, conditions: []metav1.Condition{
+				{Type: "A", Status: metav1.ConditionTrue, ObservedGeneration: 2},
+			}},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				// Act
+				service.SetSummary(obj, &test.conditions, "A", "B")
+
+				// Assert
+				summary := service.GetStatus(test.conditions)
+				assert.Equal(t, test.status, summary.Status)
+				assert.Equal(t, test.reason, summary.Reason)
+			})
+		}
+	})
+
+	t.Run("Mirror", func(t *testing.T) {
+		// Arrange
+		conditions := []metav1.Condition{}
+		source := []metav1.Condition{{Type: "Available", Status: metav1.ConditionFalse, Reason: "Degraded", Message: "message"}}
+
+		// Act
+		service.Mirror(obj, &conditions, source, "Available")
+
+		// Assert
+		assert.Equal(t, metav1.ConditionFalse, conditions[0].Status)
+		assert.Equal(t, "Degraded", conditions[0].Reason)
+		assert.Equal(t, int64(2), conditions[0].ObservedGeneration)
+
+		// Act
+		service.Mirror(obj, &conditions, nil, "Available")
+
+		// Assert
+		assert.True(t, service.IsStatusUnknown(conditions))
+	})
+}
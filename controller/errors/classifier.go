@@ -0,0 +1,236 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	pmerrors "github.com/platform-mesh/golang-commons/errors"
+)
+
+// Decision describes how the reconciler handles an error
+type Decision struct {
+	// Retry requeues the object, otherwise the reconciliation ends without error
+	Retry bool
+	// RequeueAfter requeues the object after the delay instead of the rate limited backoff, if Retry is set. The
+	// lifecycle then does not return the error, so it is neither counted as reconcile error nor backed off. Only use it
+	// for expected waits, e.g. for a dependency.
+	RequeueAfter time.Duration
+	// Sentry reports the error to Sentry
+	Sentry bool
+}
+
+// ClassifierFunc returns the decision for an error and whether the error is known to the classifier
+type ClassifierFunc func(err error) (Decision, bool)
+
+type ClassifierConfig struct {
+	// Fallback is the decision for errors unknown to all classifiers
+	Fallback Decision
+	// DependencyRequeueDelay is the delay for NotFound errors of dependencies marked with Dependency, which do not
+	// exist yet
+	DependencyRequeueDelay time.Duration
+}
+
+var defaultClassifierConfig = ClassifierConfig{
+	Fallback:               Decision{Retry: true, Sentry: true},
+	DependencyRequeueDelay: 10 * time.Second,
+}
+
+type ClassifierOption func(*ClassifierConfig)
+
+func WithFallback(fallback Decision) ClassifierOption {
+	return func(c *ClassifierConfig) {
+		c.Fallback = fallback
+	}
+}
+
+func WithDependencyRequeueDelay(delay time.Duration) ClassifierOption {
+	return func(c *ClassifierConfig) {
+		c.DependencyRequeueDelay = delay
+	}
+}
+
+// Classifier maps errors to decisions. Registered classifiers are consulted in registration order before the
+// built-in ones, so that domain errors can override the defaults.
+type Classifier struct {
+	mu         sync.RWMutex
+	registered []ClassifierFunc
+	builtin    []ClassifierFunc
+	fallback   Decision
+}
+
+func NewClassifier(opts ...ClassifierOption) *Classifier {
+	cfg := defaultClassifierConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Classifier{builtin: builtinClassifiers(cfg), fallback: cfg.Fallback}
+}
+
+// Register adds classifiers for domain errors
+func (c *Classifier) Register(classifiers ...ClassifierFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.registered = append(c.registered, classifiers...)
+}
+
+// Decide returns the decision for the error and whether any classifier knows the error
+func (c *Classifier) Decide(err error) (Decision, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, classifiers := range [][]ClassifierFunc{c.registered, c.builtin} {
+		for _, classify := range classifiers {
+			if decision, ok := classify(err); ok {
+				return decision, true
+			}
+		}
+	}
+	return c.fallback, false
+}
+
+// Classify returns an OperatorError for the error, or nil if the error is nil. If the decision requests a delay,
+// Err returns a RequeueError carrying the delay, which the lifecycle uses to requeue the object.
+func (c *Classifier) Classify(err error) pmerrors.OperatorError {
+	if err == nil {
+		return nil
+	}
+	decision, _ := c.Decide(err)
+	if decision.Retry && decision.RequeueAfter > 0 {
+		err = &RequeueError{Err: err, After: decision.RequeueAfter}
+	}
+	return pmerrors.NewOperatorError(err, decision.Retry, decision.Sentry)
+}
+
+var defaultClassifier = NewClassifier()
+
+// Register adds classifiers for domain errors to the default classifier
+func Register(classifiers ...ClassifierFunc) {
+	defaultClassifier.Register(classifiers...)
+}
+
+// Classify returns an OperatorError for the error using the default classifier
+func Classify(err error) pmerrors.OperatorError {
+	return defaultClassifier.Classify(err)
+}
+
+// RequeueError requests to requeue the object after a delay instead of the rate limited backoff
+type RequeueError struct {
+	Err   error
+	After time.Duration
+}
+
+func (e *RequeueError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequeueError) Unwrap() error {
+	return e.Err
+}
+
+// RequeueAfter returns the delay requested by a RequeueError in the chain of the error
+func RequeueAfter(err error) (time.Duration, bool) {
+	var requeueErr *RequeueError
+	if errors.As(err, &requeueErr) && requeueErr.After > 0 {
+		return requeueErr.After, true
+	}
+	return 0, false
+}
+
+// DependencyError marks an error returned while reading a dependency of the reconciled object. NotFound errors of
+// dependencies are requeued after a delay, since the dependency might not exist yet. Other NotFound errors are not
+// classified.
+type DependencyError struct {
+	Err error
+}
+
+func (e *DependencyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DependencyError) Unwrap() error {
+	return e.Err
+}
+
+// Dependency marks the error as error of a dependency, or returns nil if the error is nil
+func Dependency(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &DependencyError{Err: err}
+}
+
+func builtinClassifiers(cfg ClassifierConfig) []ClassifierFunc {
+	return []ClassifierFunc{
+		classifyDependency(cfg.DependencyRequeueDelay),
+		classifyWebhook,
+		classifyKubernetes,
+		classifyContext,
+		classifyGRPC,
+		classifyNetwork,
+	}
+}
+
+func classifyDependency(delay time.Duration) ClassifierFunc {
+	return func(err error) (Decision, bool) {
+		var dependencyErr *DependencyError
+		if !errors.As(err, &dependencyErr) || !k8sErrors.IsNotFound(dependencyErr) {
+			return Decision{}, false
+		}
+		return Decision{Retry: true, RequeueAfter: delay}, true
+	}
+}
+
+// classifyWebhook covers failing or timed out admission webhooks, which are reported as internal errors by the API
+// server but are not caused by the operator
+func classifyWebhook(err error) (Decision, bool) {
+	var statusErr k8sErrors.APIStatus
+	if !errors.As(err, &statusErr) || !strings.Contains(statusErr.Status().Message, "failed calling webhook") {
+		return Decision{}, false
+	}
+	return Decision{Retry: true}, true
+}
+
+// classifyKubernetes retries the errors retried by IsRetriable with the rate limited backoff and reports internal
+// errors of the API server
+func classifyKubernetes(err error) (Decision, bool) {
+	if retry, _ := IsRetriable(err); !retry {
+		return Decision{}, false
+	}
+	return Decision{Retry: true, Sentry: k8sErrors.IsInternalError(err)}, true
+}
+
+func classifyContext(err error) (Decision, bool) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return Decision{Retry: true}, true
+	}
+	return Decision{}, false
+}
+
+// classifyGRPC covers transient errors of gRPC services like OpenFGA
+func classifyGRPC(err error) (Decision, bool) {
+	s, ok := status.FromError(err)
+	if !ok {
+		return Decision{}, false
+	}
+	switch s.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return Decision{Retry: true}, true
+	}
+	return Decision{}, false
+}
+
+func classifyNetwork(err error) (Decision, bool) {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return Decision{Retry: true}, true
+	}
+	return Decision{}, false
+}
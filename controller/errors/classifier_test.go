@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var errDomain = errors.New("domain error")
+
+func TestClassifier(t *testing.T) {
+	notFound := k8sErrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "dependency")
+	webhook := k8sErrors.NewInternalError(errors.New(`failed calling webhook "accounts.platform-mesh.io": context deadline exceeded`))
+
+	tests := []struct {
+		name     string
+		err      error
+		decision Decision
+		known    bool
+	}{
+		{name: "conflict", err: k8sErrors.NewConflict(schema.GroupResource{}, "a", errors.New("conflict")), decision: Decision{Retry: true}, known: true},
+		{name: "client delay", err: k8sErrors.NewTooManyRequests("slow down", 3), decision: Decision{Retry: true}, known: true},
+		{name: "internal error", err: k8sErrors.NewInternalError(errors.New("boom")), decision: Decision{Retry: true, Sentry: true}, known: true},
+		{name: "webhook timeout", err: webhook, decision: Decision{Retry: true}, known: true},
+		{name: "dependency not found", err: fmt.Errorf("failed to get dependency: %w", Dependency(notFound)), decision: Decision{Retry: true, RequeueAfter: 10 * time.Second}, known: true},
+		{name: "dependency conflict", err: Dependency(k8sErrors.NewConflict(schema.GroupResource{}, "a", errors.New("conflict"))), decision: Decision{Retry: true}, known: true},
+		{name: "not found", err: notFound, decision: Decision{Retry: true, Sentry: true}},
+		{name: "deadline exceeded", err: fmt.Errorf("request failed: %w", context.DeadlineExceeded), decision: Decision{Retry: true}, known: true},
+		{name: "grpc unavailable", err: status.Error(codes.Unavailable, "openfga is down"), decision: Decision{Retry: true}, known: true},
+		{name: "network error", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, decision: Decision{Retry: true}, known: true},
+		{name: "grpc invalid argument", err: status.Error(codes.InvalidArgument, "invalid"), decision: Decision{Retry: true, Sentry: true}},
+		{name: "unknown error", err: errors.New("oh nose"), decision: Decision{Retry: true, Sentry: true}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Act
+			decision, known := NewClassifier().Decide(test.err)
+
+			// Assert
+			assert.Equal(t, test.decision, decision)
+			assert.Equal(t, test.known, known)
+		})
+	}
+
+	t.Run("registered classifiers take precedence", func(t *testing.T) {
+		// Arrange
+		classifier := NewClassifier(WithFallback(Decision{}))
+		classifier.Register(func(err error) (Decision, bool) {
+			if errors.Is(err, errDomain) || k8sErrors.IsNotFound(err) {
+				return Decision{Sentry: true}, true
+			}
+			return Decision{}, false
+		})
+
+		// Act & Assert
+		decision, known := classifier.Decide(fmt.Errorf("wrapped: %w", errDomain))
+		assert.True(t, known)
+		assert.Equal(t, Decision{Sentry: true}, decision)
+		decision, _ = classifier.Decide(notFound)
+		assert.Equal(t, Decision{Sentry: true}, decision)
+		decision, known = classifier.Decide(errors.New("oh nose"))
+		assert.False(t, known)
+		assert.Equal(t, Decision{}, decision)
+	})
+
+	t.Run("classifies into an operator error", func(t *testing.T) {
+		// Arrange
+		classifier := NewClassifier(WithDependencyRequeueDelay(time.Minute))
+
+		// Act
+		oErr := classifier.Classify(Dependency(notFound))
+
+		// Assert
+		assert.True(t, oErr.Retry())
+		assert.False(t, oErr.Sentry())
+		assert.True(t, k8sErrors.IsNotFound(oErr.Err()))
+		delay, ok := RequeueAfter(oErr.Err())
+		assert.True(t, ok)
+		assert.Equal(t, time.Minute, delay)
+	})
+
+	t.Run("classifies errors without delay", func(t *testing.T) {
+		oErr := NewClassifier().Classify(errors.New("oh nose"))
+
+		assert.True(t, oErr.Retry())
+		assert.True(t, oErr.Sentry())
+		_, ok := RequeueAfter(oErr.Err())
+		assert.False(t, ok)
+	})
+
+	t.Run("returns nil for nil errors", func(t *testing.T) {
+		assert.Nil(t, Classify(nil))
+		assert.Nil(t, Dependency(nil))
+	})
+}
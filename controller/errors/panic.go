@@ -0,0 +1,33 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PanicError is a recovered panic of a subroutine
+type PanicError struct {
+	Subroutine string
+	// Value is the value passed to panic
+	Value any
+	// Stack is the stack trace of the panicking goroutine
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("subroutine %s panicked: %v", e.Subroutine, e.Value)
+}
+
+// Unwrap returns the value passed to panic if it is an error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// IsPanic returns whether the error is caused by a recovered panic
+func IsPanic(err error) bool {
+	var panicErr *PanicError
+	return errors.As(err, &panicErr)
+}
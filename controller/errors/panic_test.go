@@ -0,0 +1,28 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPanicError(t *testing.T) {
+	t.Run("wraps error values", func(t *testing.T) {
+		cause := errors.New("cause")
+		err := fmt.Errorf("reconcile failed: %w", &PanicError{Subroutine: "sub", Value: cause})
+
+		assert.EqualError(t, err, "reconcile failed: subroutine sub panicked: cause")
+		assert.True(t, IsPanic(err))
+		assert.ErrorIs(t, err, cause)
+	})
+
+	t.Run("does not wrap other values", func(t *testing.T) {
+		err := &PanicError{Subroutine: "sub", Value: 42}
+
+		assert.EqualError(t, err, "subroutine sub panicked: 42")
+		assert.Nil(t, err.Unwrap())
+		assert.False(t, IsPanic(errors.New("other")))
+	})
+}
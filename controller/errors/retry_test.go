@@ -1,12 +1,14 @@
 package errors
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestRetry(t *testing.T) {
@@ -47,3 +49,28 @@ func TestRetry(t *testing.T) {
 		assert.NotEqualf(t, time.Duration(0), result.RequeueAfter, "Expected requeueAfter to be set, but got %v", result.RequeueAfter)
 	})
 }
+
+func TestRetryIgnoresClassifier(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "not found", err: k8sErrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "foo")},
+		{name: "plain error", err: fmt.Errorf("oh nose")},
+		{name: "canceled context", err: fmt.Errorf("request failed: %w", context.Canceled)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			Register(func(error) (Decision, bool) { return Decision{Retry: true, RequeueAfter: time.Minute}, true })
+			t.Cleanup(func() { defaultClassifier = NewClassifier() })
+
+			// Act
+			retriable, result := IsRetriable(tt.err)
+
+			// Assert
+			assert.False(t, retriable)
+			assert.Equal(t, time.Duration(0), result.RequeueAfter)
+		})
+	}
+}
@@ -0,0 +1,380 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/platform-mesh/golang-commons/logger"
+)
+
+const (
+	// DebugClaimLabel is set on all claim leases
+	DebugClaimLabel = "debug.platform-mesh.io/claim"
+	// DebugClaimNameAnnotation and DebugClaimNamespaceAnnotation reference the claimed object on a claim lease
+	DebugClaimNameAnnotation      = "debug.platform-mesh.io/claimed-name"
+	DebugClaimNamespaceAnnotation = "debug.platform-mesh.io/claimed-namespace"
+)
+
+type ClaimsConfig struct {
+	// Developer is the name of the developer claiming objects, empty for production controllers
+	Developer string
+	// ClaimDuration is the duration after which a claim not renewed by the developer expires
+	ClaimDuration time.Duration
+	// RefreshInterval is the interval in which claims are renewed and refreshed
+	RefreshInterval time.Duration
+}
+
+var defaultClaimsConfig = ClaimsConfig{
+	ClaimDuration:   2 * time.Minute,
+	RefreshInterval: 20 * time.Second,
+}
+
+func (c ClaimsConfig) validate() error {
+	if c.ClaimDuration <= 0 {
+		return fmt.Errorf("the claim duration should be positive")
+	}
+	if c.RefreshInterval <= 0 || c.RefreshInterval >= c.ClaimDuration {
+		return fmt.Errorf("the refresh interval should be positive and less than the claim duration")
+	}
+	return nil
+}
+
+type ClaimsOption func(*ClaimsConfig)
+
+func WithDeveloper(developer string) ClaimsOption {
+	return func(c *ClaimsConfig) {
+		c.Developer = developer
+	}
+}
+
+func WithClaimDuration(d time.Duration) ClaimsOption {
+	return func(c *ClaimsConfig) {
+		c.ClaimDuration = d
+	}
+}
+
+func WithRefreshInterval(d time.Duration) ClaimsOption {
+	return func(c *ClaimsConfig) {
+		c.RefreshInterval = d
+	}
+}
+
+type claim struct {
+	uid    types.UID
+	holder string
+	expiry time.Time
+}
+
+// Claims routes objects between production and developer controllers based on short-lived claim leases naming
+// the developer. A developer controller only processes objects it claimed, a production controller ignores
+// objects with an active claim and takes them back once the claim expires or is released.
+// Claims implements manager.Runnable and has to be added to the manager.
+type Claims struct {
+	cfg       ClaimsConfig
+	client    client.Client
+	namespace string
+	log       *logger.Logger
+	now       func() time.Time
+
+	mu      sync.RWMutex
+	claims  map[types.NamespacedName]claim
+	events  chan event.GenericEvent
+	sourced atomic.Bool
+	// stopped is closed once Start returns, so that pending hand-overs are dropped
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// NewClaims creates Claims maintaining the claim leases in namespace. Without a developer, the claims of all
+// developers are respected, otherwise objects have to be claimed by the developer.
+func NewClaims(cl client.Client, namespace string, log *logger.Logger, opts ...ClaimsOption) (*Claims, error) {
+	cfg := defaultClaimsConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		return nil, fmt.Errorf("namespace should not be empty")
+	}
+	return &Claims{
+		cfg:       cfg,
+		client:    cl,
+		namespace: namespace,
+		log:       log.ComponentLogger("claims").MustChildLoggerWithAttributes("developer", cfg.Developer),
+		now:       time.Now,
+		claims:    map[types.NamespacedName]claim{},
+		events:    make(chan event.GenericEvent),
+		stopped:   make(chan struct{}),
+	}, nil
+}
+
+func claimLeaseName(uid types.UID) string {
+	return fmt.Sprintf("debug-claim-%s", uid)
+}
+
+// Claim claims the object for the developer or renews an existing claim. It fails if the object is
+// actively claimed by another developer.
+func (c *Claims) Claim(ctx context.Context, obj client.Object) error {
+	if c.cfg.Developer == "" {
+		return fmt.Errorf("objects can only be claimed by a developer")
+	}
+	if obj.GetUID() == "" {
+		return fmt.Errorf("object %s has no uid", client.ObjectKeyFromObject(obj))
+	}
+
+	now := metav1.NewMicroTime(c.now())
+	lease := &coordinationv1.Lease{}
+	err := c.client.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: claimLeaseName(obj.GetUID())}, lease)
+	if kerrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      claimLeaseName(obj.GetUID()),
+				Namespace: c.namespace,
+				Labels:    map[string]string{DebugClaimLabel: "true"},
+				Annotations: map[string]string{
+					DebugClaimNameAnnotation:      obj.GetName(),
+					DebugClaimNamespaceAnnotation: obj.GetNamespace(),
+				},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(c.cfg.Developer),
+				LeaseDurationSeconds: ptr.To(int32(c.cfg.ClaimDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if err := c.client.Create(ctx, lease); err != nil {
+			return fmt.Errorf("failed to create claim: %w", err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get claim: %w", err)
+	} else {
+		if _, cl, active := c.claimOf(lease); active && cl.holder != c.cfg.Developer {
+			return fmt.Errorf("object %s is claimed by %s until %s", client.ObjectKeyFromObject(obj), cl.holder, cl.expiry.Format(time.RFC3339))
+		}
+		if ptr.Deref(lease.Spec.HolderIdentity, "") != c.cfg.Developer {
+			lease.Spec.AcquireTime = &now
+		}
+		lease.Spec.HolderIdentity = ptr.To(c.cfg.Developer)
+		lease.Spec.LeaseDurationSeconds = ptr.To(int32(c.cfg.ClaimDuration.Seconds()))
+		lease.Spec.RenewTime = &now
+		if err := c.client.Update(ctx, lease); err != nil {
+			return fmt.Errorf("failed to renew claim: %w", err)
+		}
+	}
+
+	return c.Refresh(ctx)
+}
+
+// Release releases the claim of the developer on the object
+func (c *Claims) Release(ctx context.Context, obj client.Object) error {
+	return c.release(ctx, obj.GetUID())
+}
+
+func (c *Claims) release(ctx context.Context, uid types.UID) error {
+	lease := &coordinationv1.Lease{}
+	err := c.client.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: claimLeaseName(uid)}, lease)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != c.cfg.Developer {
+		return nil
+	}
+	return client.IgnoreNotFound(c.client.Delete(ctx, lease))
+}
+
+// Holder returns the developer actively claiming the object with the key, or an empty string
+func (c *Claims) Holder(key types.NamespacedName) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cl, ok := c.claims[key]
+	if !ok || !c.now().Before(cl.expiry) {
+		return ""
+	}
+	return cl.holder
+}
+
+// Accepts returns whether the object with the key is claimed by the developer. Without a developer,
+// it returns whether the object has no active claim.
+func (c *Claims) Accepts(key types.NamespacedName) bool {
+	return c.Holder(key) == c.cfg.Developer
+}
+
+// Predicate returns a predicate filtering events of objects not accepted by the claims
+func (c *Claims) Predicate() predicate.Predicate {
+	accept := func(obj client.Object) bool {
+		return c.Accepts(client.ObjectKeyFromObject(obj))
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return accept(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return accept(e.ObjectNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return accept(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return accept(e.Object) },
+	}
+}
+
+// Source returns a source enqueuing objects whenever they are handed over, i.e. objects newly claimed by the
+// developer, or without a developer, objects whose claim expired or was released
+func (c *Claims) Source() source.Source {
+	c.sourced.Store(true)
+	return source.Channel(c.events, &handler.EnqueueRequestForObject{})
+}
+
+// Start renews the claims of the developer and refreshes all claims until the context is done.
+// The claims of the developer are released on shutdown.
+func (c *Claims) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.cfg.RefreshInterval)
+	defer ticker.Stop()
+	defer c.stopOnce.Do(func() { close(c.stopped) })
+	defer c.releaseAll()
+
+	for {
+		if err := c.renew(ctx); err != nil {
+			c.log.Error().Err(err).Msg("failed to renew claims")
+		}
+		if err := c.Refresh(ctx); err != nil {
+			c.log.Error().Err(err).Msg("failed to refresh claims")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns false, since claims have to be known by every replica
+func (c *Claims) NeedLeaderElection() bool {
+	return false
+}
+
+// Refresh reads all claims and hands over the objects whose claim changed. Expired claims are deleted.
+func (c *Claims) Refresh(ctx context.Context) error {
+	leases := &coordinationv1.LeaseList{}
+	if err := c.client.List(ctx, leases, client.InNamespace(c.namespace), client.MatchingLabels{DebugClaimLabel: "true"}); err != nil {
+		return fmt.Errorf("failed to list claims: %w", err)
+	}
+
+	claims := map[types.NamespacedName]claim{}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		key, cl, active := c.claimOf(lease)
+		if !active {
+			if err := c.client.Delete(ctx, lease); client.IgnoreNotFound(err) != nil {
+				c.log.Warn().Err(err).Str("lease", lease.Name).Msg("failed to delete expired claim")
+			}
+			continue
+		}
+		claims[key] = cl
+	}
+
+	c.mu.Lock()
+	previous := c.claims
+	c.claims = claims
+	c.mu.Unlock()
+
+	var handedOver []types.NamespacedName
+	for key, cl := range claims {
+		if c.cfg.Developer != "" && cl.holder == c.cfg.Developer && previous[key].holder != c.cfg.Developer {
+			handedOver = append(handedOver, key)
+		}
+	}
+	for key, cl := range previous {
+		if _, ok := claims[key]; !ok && c.cfg.Developer == "" {
+			c.log.Info().Str("name", key.Name).Str("namespace", key.Namespace).Str("holder", cl.holder).Msg("claim ended, taking back object")
+			handedOver = append(handedOver, key)
+		}
+	}
+
+	if len(handedOver) > 0 && c.sourced.Load() {
+		// The hand-over outlives the context of the caller, e.g. of a request claiming an object
+		go c.handOver(handedOver)
+	}
+	return nil
+}
+
+func (c *Claims) handOver(keys []types.NamespacedName) {
+	for _, key := range keys {
+		obj := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+		select {
+		case c.events <- event.GenericEvent{Object: obj}:
+		case <-c.stopped:
+			return
+		}
+	}
+}
+
+func (c *Claims) claimOf(lease *coordinationv1.Lease) (types.NamespacedName, claim, bool) {
+	key := types.NamespacedName{Name: lease.Annotations[DebugClaimNameAnnotation], Namespace: lease.Annotations[DebugClaimNamespaceAnnotation]}
+	if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return key, claim{}, false
+	}
+	cl := claim{
+		uid:    types.UID(strings.TrimPrefix(lease.Name, claimLeaseName(""))),
+		holder: *lease.Spec.HolderIdentity,
+		expiry: lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second),
+	}
+	return key, cl, c.now().Before(cl.expiry)
+}
+
+func (c *Claims) ownClaims() []types.UID {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var own []types.UID
+	for _, cl := range c.claims {
+		if cl.holder == c.cfg.Developer {
+			own = append(own, cl.uid)
+		}
+	}
+	return own
+}
+
+func (c *Claims) renew(ctx context.Context) error {
+	if c.cfg.Developer == "" {
+		return nil
+	}
+	for _, uid := range c.ownClaims() {
+		lease := &coordinationv1.Lease{}
+		if err := c.client.Get(ctx, client.ObjectKey{Namespace: c.namespace, Name: claimLeaseName(uid)}, lease); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		lease.Spec.RenewTime = ptr.To(metav1.NewMicroTime(c.now()))
+		if err := c.client.Update(ctx, lease); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Claims) releaseAll() {
+	if c.cfg.Developer == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.RefreshInterval)
+	defer cancel()
+	for _, uid := range c.ownClaims() {
+		if err := c.release(ctx, uid); err != nil {
+			c.log.Error().Err(err).Msg("failed to release claim")
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package filter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func newTestClaims(t *testing.T, cl client.Client, now *time.Time, opts ...ClaimsOption) *Claims {
+	c, err := NewClaims(cl, "default", testlogger.New().Logger, opts...)
+	require.NoError(t, err)
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestNewClaims(t *testing.T) {
+	log := testlogger.New().Logger
+	cl := fake.NewClientBuilder().Build()
+
+	_, err := NewClaims(cl, "", log)
+	assert.Error(t, err)
+	_, err = NewClaims(cl, "default", log, WithRefreshInterval(time.Hour))
+	assert.Error(t, err)
+	_, err = NewClaims(cl, "default", log, WithClaimDuration(0))
+	assert.Error(t, err)
+
+	_, err = NewClaims(cl, "default", log, WithDeveloper("jane"))
+	assert.NoError(t, err)
+}
+
+func TestClaims(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cl := fake.NewClientBuilder().Build()
+	production := newTestClaims(t, cl, &now)
+	jane := newTestClaims(t, cl, &now, WithDeveloper("jane"))
+	john := newTestClaims(t, cl, &now, WithDeveloper("john"))
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", UID: "uid"}}
+	other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "bar", UID: "other"}}
+	key := client.ObjectKeyFromObject(obj)
+
+	t.Run("production accepts unclaimed objects", func(t *testing.T) {
+		require.NoError(t, production.Refresh(ctx))
+		assert.True(t, production.Accepts(key))
+		assert.False(t, jane.Accepts(key))
+	})
+
+	t.Run("only the claiming developer accepts claimed objects", func(t *testing.T) {
+		require.NoError(t, jane.Claim(ctx, obj))
+		require.NoError(t, production.Refresh(ctx))
+		require.NoError(t, john.Refresh(ctx))
+
+		assert.Equal(t, "jane", production.Holder(key))
+		assert.False(t, production.Accepts(key))
+		assert.True(t, jane.Accepts(key))
+		assert.False(t, john.Accepts(key))
+		assert.True(t, production.Accepts(client.ObjectKeyFromObject(other)))
+	})
+
+	t.Run("rejects claims of other developers", func(t *testing.T) {
+		assert.Error(t, john.Claim(ctx, obj))
+		assert.Error(t, production.Claim(ctx, obj))
+	})
+
+	t.Run("production takes back objects once the claim expired", func(t *testing.T) {
+		now = now.Add(3 * time.Minute)
+		assert.True(t, production.Accepts(key))
+
+		require.NoError(t, production.Refresh(ctx))
+		leases := &coordinationv1.LeaseList{}
+		require.NoError(t, cl.List(ctx, leases, client.MatchingLabels{DebugClaimLabel: "true"}))
+		assert.Empty(t, leases.Items)
+	})
+
+	t.Run("renews and releases the claims of the developer", func(t *testing.T) {
+		require.NoError(t, john.Claim(ctx, obj))
+		now = now.Add(time.Minute)
+		require.NoError(t, john.renew(ctx))
+		now = now.Add(90 * time.Second)
+		require.NoError(t, production.Refresh(ctx))
+		assert.Equal(t, "john", production.Holder(key))
+
+		runCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		require.NoError(t, john.Start(runCtx))
+		require.NoError(t, production.Refresh(ctx))
+		assert.True(t, production.Accepts(key))
+	})
+}
+
+func TestClaims_Predicate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	cl := fake.NewClientBuilder().Build()
+	production := newTestClaims(t, cl, &now)
+	jane := newTestClaims(t, cl, &now, WithDeveloper("jane"))
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", UID: "uid"}}
+	require.NoError(t, jane.Claim(ctx, obj))
+	require.NoError(t, production.Refresh(ctx))
+
+	assert.False(t, production.Predicate().Create(event.CreateEvent{Object: obj}))
+	assert.False(t, production.Predicate().Update(event.UpdateEvent{ObjectOld: obj, ObjectNew: obj}))
+	assert.True(t, jane.Predicate().Delete(event.DeleteEvent{Object: obj}))
+	assert.True(t, jane.Predicate().Generic(event.GenericEvent{Object: obj}))
+}
+
+func TestClaims_Source(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Now()
+	cl := fake.NewClientBuilder().Build()
+	production := newTestClaims(t, cl, &now)
+	jane := newTestClaims(t, cl, &now, WithDeveloper("jane"))
+
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+	require.NoError(t, production.Source().Start(ctx, queue))
+	devQueue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer devQueue.ShutDown()
+	require.NoError(t, jane.Source().Start(ctx, devQueue))
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", UID: "uid"}}
+	require.NoError(t, jane.Claim(ctx, obj))
+	require.NoError(t, production.Refresh(ctx))
+	assert.Eventually(t, func() bool { return devQueue.Len() == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, queue.Len())
+
+	require.NoError(t, jane.Release(ctx, obj))
+	require.NoError(t, production.Refresh(ctx))
+	assert.Eventually(t, func() bool { return queue.Len() == 1 }, time.Second, 10*time.Millisecond)
+	item, _ := queue.Get()
+	assert.Equal(t, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}, item)
+}
+
+func TestClaims_HandOverOutlivesCallerContext(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Now()
+	cl := fake.NewClientBuilder().Build()
+	jane := newTestClaims(t, cl, &now, WithDeveloper("jane"))
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+	require.NoError(t, jane.Source().Start(ctx, queue))
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", UID: "uid"}}
+
+	// Act
+	requestCtx, cancelRequest := context.WithCancel(context.Background())
+	require.NoError(t, jane.Claim(requestCtx, obj))
+	cancelRequest()
+
+	// Assert
+	assert.Eventually(t, func() bool { return queue.Len() == 1 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,144 @@
+package predicates
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/platform-mesh/golang-commons/config"
+)
+
+// NamespacePredicate returns a predicate accepting objects in the allowed namespaces which are not denied.
+// An empty allow list allows all namespaces. Cluster scoped objects are always accepted.
+func NamespacePredicate(allowed, denied []string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		ns := obj.GetNamespace()
+		if ns == "" {
+			return true
+		}
+		if slices.Contains(denied, ns) {
+			return false
+		}
+		return len(allowed) == 0 || slices.Contains(allowed, ns)
+	})
+}
+
+// LabelSelectorPredicate returns a predicate accepting objects matching the label selector, e.g. "team=a,!legacy".
+// An empty selector matches all objects.
+func LabelSelectorPredicate(selector string) (predicate.Predicate, error) {
+	s, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector %q: %w", selector, err)
+	}
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return s.Matches(labels.Set(obj.GetLabels()))
+	}), nil
+}
+
+// AnnotationChangedPredicate returns a predicate accepting updates changing one of the annotations with the given keys,
+// or any annotation if no keys are given. Create, delete and generic events are accepted.
+func AnnotationChangedPredicate(keys ...string) predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			oldAnnotations, newAnnotations := e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations()
+			if len(keys) == 0 {
+				return !equality.Semantic.DeepEqual(oldAnnotations, newAnnotations)
+			}
+			for _, key := range keys {
+				oldValue, oldOk := oldAnnotations[key]
+				newValue, newOk := newAnnotations[key]
+				if oldOk != newOk || oldValue != newValue {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// GenerationOrLabelOrFinalizerChangedPredicate returns a predicate accepting updates changing the generation, the labels,
+// the finalizers or the deletion timestamp of an object. Create, delete and generic events are accepted.
+func GenerationOrLabelOrFinalizerChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+				!equality.Semantic.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+				!slices.Equal(e.ObjectOld.GetFinalizers(), e.ObjectNew.GetFinalizers()) ||
+				!e.ObjectOld.GetDeletionTimestamp().Equal(e.ObjectNew.GetDeletionTimestamp())
+		},
+	}
+}
+
+// IgnoreStatusOnlyUpdatesPredicate returns a predicate dropping updates which only changed the status of an object,
+// e.g. the updates caused by the lifecycle itself. Create, delete and generic events are accepted.
+func IgnoreStatusOnlyUpdatesPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			oldContent, err := withoutStatus(e.ObjectOld)
+			if err != nil {
+				return true
+			}
+			newContent, err := withoutStatus(e.ObjectNew)
+			if err != nil {
+				return true
+			}
+			return !equality.Semantic.DeepEqual(oldContent, newContent)
+		},
+	}
+}
+
+// withoutStatus returns the content of the object without the status and the metadata changed on every write
+func withoutStatus(obj client.Object) (map[string]any, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	delete(content, "status")
+	if metadata, ok := content["metadata"].(map[string]any); ok {
+		delete(metadata, "resourceVersion")
+		delete(metadata, "managedFields")
+	}
+	return content, nil
+}
+
+// FromConfig returns the predicates configured in the filter configuration. The predicates can be passed as
+// eventPredicates to SetupWithManagerBuilder of the controller-runtime and multicluster lifecycle managers.
+func FromConfig(cfg config.FilterConfig) ([]predicate.Predicate, error) {
+	var predicates []predicate.Predicate
+	if len(cfg.AllowedNamespaces) > 0 || len(cfg.DeniedNamespaces) > 0 {
+		predicates = append(predicates, NamespacePredicate(cfg.AllowedNamespaces, cfg.DeniedNamespaces))
+	}
+	if cfg.LabelSelector != "" {
+		p, err := LabelSelectorPredicate(cfg.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, p)
+	}
+	if cfg.IgnoreStatusOnlyUpdates {
+		predicates = append(predicates, IgnoreStatusOnlyUpdatesPredicate())
+	}
+	if cfg.SignificantChangesOnly {
+		changed := GenerationOrLabelOrFinalizerChangedPredicate()
+		if len(cfg.WatchedAnnotations) > 0 {
+			changed = predicate.Or(changed, AnnotationChangedPredicate(cfg.WatchedAnnotations...))
+		}
+		predicates = append(predicates, changed)
+	}
+	return predicates, nil
+}
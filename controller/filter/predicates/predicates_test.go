@@ -0,0 +1,139 @@
+package predicates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/platform-mesh/golang-commons/config"
+	"github.com/platform-mesh/golang-commons/controller/testSupport"
+)
+
+func update(p predicate.Predicate, oldObj, newObj *testSupport.TestApiObject) bool {
+	return p.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj})
+}
+
+func TestNamespacePredicate(t *testing.T) {
+	p := NamespacePredicate([]string{"a", "b"}, []string{"b"})
+
+	for ns, expected := range map[string]bool{"a": true, "b": false, "c": false, "": true} {
+		obj := &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Namespace: ns}}
+		assert.Equal(t, expected, p.Create(event.CreateEvent{Object: obj}), ns)
+		assert.Equal(t, expected, p.Generic(event.GenericEvent{Object: obj}), ns)
+	}
+
+	obj := &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Namespace: "c"}}
+	assert.True(t, NamespacePredicate(nil, []string{"b"}).Delete(event.DeleteEvent{Object: obj}))
+}
+
+func TestLabelSelectorPredicate(t *testing.T) {
+	_, err := LabelSelectorPredicate("a=")
+	assert.NoError(t, err)
+	_, err = LabelSelectorPredicate("a==b==c")
+	assert.Error(t, err)
+
+	p, err := LabelSelectorPredicate("team=a,!legacy")
+	require.NoError(t, err)
+	assert.True(t, p.Create(event.CreateEvent{Object: &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a"}}}}))
+	assert.False(t, p.Create(event.CreateEvent{Object: &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a", "legacy": ""}}}}))
+	assert.False(t, p.Create(event.CreateEvent{Object: &testSupport.TestApiObject{}}))
+}
+
+func TestAnnotationChangedPredicate(t *testing.T) {
+	oldObj := &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"a": "1", "b": "1"}}}
+	changedA := &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"a": "2", "b": "1"}}}
+	removedB := &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"a": "1"}}}
+
+	assert.True(t, update(AnnotationChangedPredicate(), oldObj, changedA))
+	assert.False(t, update(AnnotationChangedPredicate(), oldObj, oldObj))
+	assert.True(t, update(AnnotationChangedPredicate("a"), oldObj, changedA))
+	assert.False(t, update(AnnotationChangedPredicate("a"), oldObj, removedB))
+	assert.True(t, update(AnnotationChangedPredicate("b"), oldObj, removedB))
+	assert.True(t, AnnotationChangedPredicate("a").Create(event.CreateEvent{Object: oldObj}))
+}
+
+func TestGenerationOrLabelOrFinalizerChangedPredicate(t *testing.T) {
+	p := GenerationOrLabelOrFinalizerChangedPredicate()
+	oldObj := &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+
+	t.Run("accepts generation changes", func(t *testing.T) {
+		assert.True(t, update(p, oldObj, &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Generation: 2}}))
+	})
+	t.Run("accepts label changes", func(t *testing.T) {
+		assert.True(t, update(p, oldObj, &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Generation: 1, Labels: map[string]string{"a": "b"}}}))
+	})
+	t.Run("accepts finalizer changes", func(t *testing.T) {
+		assert.True(t, update(p, oldObj, &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Generation: 1, Finalizers: []string{"f"}}}))
+	})
+	t.Run("accepts deletions", func(t *testing.T) {
+		now := metav1.Now()
+		assert.True(t, update(p, oldObj, &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Generation: 1, DeletionTimestamp: &now}}))
+	})
+	t.Run("drops other changes", func(t *testing.T) {
+		newObj := &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Generation: 1, Annotations: map[string]string{"a": "b"}}, Status: testSupport.TestStatus{Some: "value"}}
+		assert.False(t, update(p, oldObj, newObj))
+	})
+}
+
+func TestIgnoreStatusOnlyUpdatesPredicate(t *testing.T) {
+	p := IgnoreStatusOnlyUpdatesPredicate()
+	oldObj := &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", ResourceVersion: "1"}}
+
+	t.Run("drops status only updates", func(t *testing.T) {
+		newObj := &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", ResourceVersion: "2"}, Status: testSupport.TestStatus{Some: "value"}}
+		assert.False(t, update(p, oldObj, newObj))
+	})
+	t.Run("accepts metadata updates", func(t *testing.T) {
+		newObj := &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", ResourceVersion: "2", Annotations: map[string]string{"a": "b"}}}
+		assert.True(t, update(p, oldObj, newObj))
+	})
+	t.Run("accepts spec updates of objects without generation", func(t *testing.T) {
+		oldCm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo"}, Data: map[string]string{"a": "1"}}
+		newCm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo"}, Data: map[string]string{"a": "2"}}
+		assert.True(t, p.Update(event.UpdateEvent{ObjectOld: oldCm, ObjectNew: newCm}))
+	})
+}
+
+func TestFromConfig(t *testing.T) {
+	t.Run("returns no predicates by default", func(t *testing.T) {
+		predicates, err := FromConfig(config.NewDefaultConfig().Filter)
+		require.NoError(t, err)
+		assert.Empty(t, predicates)
+	})
+
+	t.Run("fails on invalid label selectors", func(t *testing.T) {
+		_, err := FromConfig(config.FilterConfig{LabelSelector: "a==b==c"})
+		assert.Error(t, err)
+	})
+
+	t.Run("combines the configured predicates", func(t *testing.T) {
+		// Arrange
+		cfg := config.FilterConfig{
+			AllowedNamespaces:      []string{"a"},
+			LabelSelector:          "team=a",
+			SignificantChangesOnly: true,
+			WatchedAnnotations:     []string{"refresh"},
+		}
+		oldObj := &testSupport.TestApiObject{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Labels: map[string]string{"team": "a"}}}
+		refreshed := oldObj.DeepCopy()
+		refreshed.Annotations = map[string]string{"refresh": "now"}
+		otherNamespace := refreshed.DeepCopy()
+		otherNamespace.Namespace = "b"
+
+		// Act
+		predicates, err := FromConfig(cfg)
+		require.NoError(t, err)
+		p := predicate.And(predicates...)
+
+		// Assert
+		assert.Len(t, predicates, 3)
+		assert.True(t, update(p, oldObj, refreshed))
+		assert.False(t, update(p, oldObj, oldObj))
+		assert.False(t, update(p, otherNamespace, otherNamespace))
+	})
+}
@@ -2,14 +2,18 @@ package api
 
 import (
 	"context"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
 	"github.com/platform-mesh/golang-commons/errors"
 	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/platform-mesh/golang-commons/sentry"
 )
 
 type Lifecycle interface {
@@ -29,12 +33,133 @@ type TerminatingLifecycle interface {
 	Terminator() string
 }
 
+// RuntimeObjectInitializers is implemented by instances carrying kcp initializers in their status
+type RuntimeObjectInitializers interface {
+	GetInitializers() []string
+	SetInitializers([]string)
+}
+
+// RuntimeObjectTerminators is implemented by instances carrying kcp terminators in their status
+type RuntimeObjectTerminators interface {
+	GetTerminators() []string
+	SetTerminators([]string)
+}
+
+// FinalizerMigratingLifecycle can be implemented to clean up or migrate
+// finalizers which are no longer owned by any subroutine, e.g. after a
+// subroutine was removed or renamed.
+type FinalizerMigratingLifecycle interface {
+	LegacyFinalizers() []LegacyFinalizer
+}
+
+// EventRecordingLifecycle can be implemented to emit kubernetes events for the
+// reconciled instance.
+type EventRecordingLifecycle interface {
+	EventRecorder() events.EventRecorder
+}
+
+// TrackingLifecycle can be implemented to track in-flight reconciles, e.g.
+// to detect reconciles stuck in a subroutine.
+type TrackingLifecycle interface {
+	ReconcileTracker() ReconcileTracker
+}
+
+// PrioritizingLifecycle can be implemented to requeue spread-driven resyncs
+// with a low priority when the controller uses a priority queue.
+type PrioritizingLifecycle interface {
+	QueuePolicy() QueuePolicy
+}
+
+// DeletionProtectingLifecycle can be implemented to configure how long the lifecycle waits before checking
+// again whether the deletion protection of an instance in deletion was removed.
+type DeletionProtectingLifecycle interface {
+	DeletionBlockedRequeueAfter() time.Duration
+}
+
+type QueuePolicy interface {
+	ResyncPriority(controller string) *int
+}
+
+type ReconcileTracker interface {
+	Track(object string, tags sentry.Tags) TrackedReconcile
+}
+
+// TrackedReconcile is a single in-flight reconcile. Done has to be called
+// once the reconcile finished.
+type TrackedReconcile interface {
+	SetSubroutine(name string)
+	Done()
+}
+
+// InputHashingLifecycle can be implemented to skip subroutines implementing
+// subroutine.InputHasher while their input hash and the generation are
+// unchanged since their last successful run.
+type InputHashingLifecycle interface {
+	SkipUnchangedSubroutines() bool
+}
+
+// SubroutineInputHash is the input hash of the last successful run of a subroutine
+type SubroutineInputHash struct {
+	Name               string `json:"name"`
+	Hash               string `json:"hash"`
+	ObservedGeneration int64  `json:"observedGeneration"`
+}
+
+// RuntimeObjectSubroutineInputHashes is implemented by instances persisting subroutine input hashes in their status
+type RuntimeObjectSubroutineInputHashes interface {
+	GetSubroutineInputHashes() []SubroutineInputHash
+	SetSubroutineInputHashes([]SubroutineInputHash)
+}
+
+// SubroutineFinalizeCheckpoint is the finalization progress of a subroutine, persisted in the status of the instance
+// until the finalizer of the subroutine is removed
+type SubroutineFinalizeCheckpoint struct {
+	Name           string   `json:"name"`
+	CompletedSteps []string `json:"completedSteps,omitempty"`
+	Cursor         string   `json:"cursor,omitempty"`
+}
+
+// DeepCopyInto copies the receiver into out
+func (in *SubroutineFinalizeCheckpoint) DeepCopyInto(out *SubroutineFinalizeCheckpoint) {
+	*out = *in
+	if in.CompletedSteps != nil {
+		out.CompletedSteps = make([]string, len(in.CompletedSteps))
+		copy(out.CompletedSteps, in.CompletedSteps)
+	}
+}
+
+// RuntimeObjectFinalizeCheckpoints is implemented by instances persisting the finalization progress of subroutines in
+// their status
+type RuntimeObjectFinalizeCheckpoints interface {
+	GetFinalizeCheckpoints() []SubroutineFinalizeCheckpoint
+	SetFinalizeCheckpoints([]SubroutineFinalizeCheckpoint)
+}
+
+// ClientInstrumentingLifecycle can be implemented to pass an instrumented
+// client labelled with the subroutine name to subroutines via the context.
+type ClientInstrumentingLifecycle interface {
+	ClientInstrumentation() ClientInstrumentation
+}
+
+type ClientInstrumentation interface {
+	Wrap(cl client.Client, subroutine string) client.Client
+}
+
+// LegacyFinalizer is a finalizer that might still be present on instances but
+// is no longer owned by the current subroutines. If MigrateTo is set, the
+// legacy finalizer is replaced by it, otherwise it is removed.
+type LegacyFinalizer struct {
+	Name      string
+	MigrateTo string
+}
+
 type PrepareContextFunc func(ctx context.Context, instance runtimeobject.RuntimeObject) (context.Context, errors.OperatorError)
 
 type Config struct {
 	OperatorName   string
 	ControllerName string
 	ReadOnly       bool
+	ReadOnlyDryRun bool
 }
 
 type ConditionManager interface {
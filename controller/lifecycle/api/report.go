@@ -0,0 +1,65 @@
+package api
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+)
+
+// SubroutineResult is the outcome of a single subroutine run
+type SubroutineResult string
+
+const (
+	SubroutineResultSuccess SubroutineResult = "Success"
+	SubroutineResultRequeue SubroutineResult = "Requeue"
+	SubroutineResultError   SubroutineResult = "Error"
+)
+
+// SubroutineReport is a compact report of the runs of a single subroutine, persisted in the status of the instance
+type SubroutineReport struct {
+	Name                string           `json:"name"`
+	LastRunTime         metav1.Time      `json:"lastRunTime"`
+	LastSuccessTime     *metav1.Time     `json:"lastSuccessTime,omitempty"`
+	Duration            metav1.Duration  `json:"duration"`
+	Result              SubroutineResult `json:"result"`
+	Attempts            int64            `json:"attempts"`
+	ConsecutiveFailures int64            `json:"consecutiveFailures,omitempty"`
+	ObservedGeneration  int64            `json:"observedGeneration"`
+}
+
+// DeepCopyInto copies the receiver into out
+func (in *SubroutineReport) DeepCopyInto(out *SubroutineReport) {
+	*out = *in
+	in.LastRunTime.DeepCopyInto(&out.LastRunTime)
+	if in.LastSuccessTime != nil {
+		out.LastSuccessTime = in.LastSuccessTime.DeepCopy()
+	}
+}
+
+// DeepCopy creates a new deep copy of the receiver
+func (in *SubroutineReport) DeepCopy() *SubroutineReport {
+	if in == nil {
+		return nil
+	}
+	out := new(SubroutineReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// RuntimeObjectSubroutineReports is implemented by instances persisting subroutine reports in their status
+type RuntimeObjectSubroutineReports interface {
+	GetSubroutineReports() []SubroutineReport
+	SetSubroutineReports([]SubroutineReport)
+}
+
+type ReportManager interface {
+	RecordSubroutineRun(reports *[]SubroutineReport, object string, observedGeneration int64, subroutine subroutine.Subroutine, start time.Time, subroutineResult ctrl.Result, subroutineErr error) bool
+}
+
+// ReportingLifecycle can be implemented to record a SubroutineReport for every subroutine run
+type ReportingLifecycle interface {
+	ReportManager() ReportManager
+}
@@ -0,0 +1,86 @@
+package api
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// CommonStatus contains the status fields used by the lifecycle. It is embedded inline into the status of CRDs:
+//
+//	type AccountStatus struct {
+//		api.CommonStatus `json:",inline"`
+//	}
+//
+// The accessor methods required by the lifecycle interfaces are generated for types marked with
+// +lifecycle:commonstatus by lifecycle-gen.
+type CommonStatus struct {
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +optional
+	NextReconcileTime metav1.Time `json:"nextReconcileTime,omitempty"`
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Terminators block the deletion of kcp logical clusters until they are removed
+	// +optional
+	Terminators []string `json:"terminators,omitempty"`
+	// Initializers block the initialization of kcp logical clusters until they are removed
+	// +optional
+	Initializers []string `json:"initializers,omitempty"`
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	SubroutineReports []SubroutineReport `json:"subroutineReports,omitempty"`
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	InputHashes []SubroutineInputHash `json:"inputHashes,omitempty"`
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	FinalizeCheckpoints []SubroutineFinalizeCheckpoint `json:"finalizeCheckpoints,omitempty"`
+}
+
+// DeepCopyInto copies the receiver into out
+func (in *CommonStatus) DeepCopyInto(out *CommonStatus) {
+	*out = *in
+	in.NextReconcileTime.DeepCopyInto(&out.NextReconcileTime)
+	if in.Conditions != nil {
+		out.Conditions = make([]metav1.Condition, len(in.Conditions))
+		for i := range in.Conditions {
+			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+	if in.Terminators != nil {
+		out.Terminators = make([]string, len(in.Terminators))
+		copy(out.Terminators, in.Terminators)
+	}
+	if in.Initializers != nil {
+		out.Initializers = make([]string, len(in.Initializers))
+		copy(out.Initializers, in.Initializers)
+	}
+	if in.SubroutineReports != nil {
+		out.SubroutineReports = make([]SubroutineReport, len(in.SubroutineReports))
+		for i := range in.SubroutineReports {
+			in.SubroutineReports[i].DeepCopyInto(&out.SubroutineReports[i])
+		}
+	}
+	if in.InputHashes != nil {
+		out.InputHashes = make([]SubroutineInputHash, len(in.InputHashes))
+		copy(out.InputHashes, in.InputHashes)
+	}
+	if in.FinalizeCheckpoints != nil {
+		out.FinalizeCheckpoints = make([]SubroutineFinalizeCheckpoint, len(in.FinalizeCheckpoints))
+		for i := range in.FinalizeCheckpoints {
+			in.FinalizeCheckpoints[i].DeepCopyInto(&out.FinalizeCheckpoints[i])
+		}
+	}
+}
+
+// DeepCopy creates a new deep copy of the receiver
+func (in *CommonStatus) DeepCopy() *CommonStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CommonStatus)
+	in.DeepCopyInto(out)
+	return out
+}
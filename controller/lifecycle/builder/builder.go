@@ -1,13 +1,23 @@
 package builder
 
 import (
+	"time"
+
+	"k8s.io/client-go/tools/events"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
 
+	pmclient "github.com/platform-mesh/golang-commons/controller/client"
+	"github.com/platform-mesh/golang-commons/controller/filter"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/controllerruntime"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/multicluster"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/priority"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/ratelimiter"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/sharding"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/trigger"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/watchdog"
 	"github.com/platform-mesh/golang-commons/logger"
 )
 
@@ -17,9 +27,22 @@ type Builder struct {
 	withConditionManagement bool
 	withSpreadingReconciles bool
 	withReadOnly            bool
+	withReadOnlyDryRun      bool
+	withSubroutineReports   bool
+	reportMinInterval       time.Duration
+	withSkipUnchanged       bool
+	protectionRequeue       time.Duration
+	instrumentation         *pmclient.Instrumentation
 	terminator              string
 	initializer             string
 	rateLimiterOptions      *[]ratelimiter.Option
+	legacyFinalizers        []api.LegacyFinalizer
+	eventRecorder           events.EventRecorder
+	watchdog                *watchdog.Watchdog
+	sharder                 *sharding.Sharder
+	debugClaims             *filter.Claims
+	trigger                 *trigger.Trigger
+	queuePolicy             *priority.Policy
 	subroutines             []subroutine.Subroutine
 	log                     *logger.Logger
 }
@@ -49,6 +72,33 @@ func (b *Builder) WithReadOnly() *Builder {
 	return b
 }
 
+func (b *Builder) WithReadOnlyDryRun() *Builder {
+	b.withReadOnly = true
+	b.withReadOnlyDryRun = true
+	return b
+}
+
+func (b *Builder) WithSubroutineReports(minInterval time.Duration) *Builder {
+	b.withSubroutineReports = true
+	b.reportMinInterval = minInterval
+	return b
+}
+
+func (b *Builder) WithSkipUnchangedSubroutines() *Builder {
+	b.withSkipUnchanged = true
+	return b
+}
+
+func (b *Builder) WithDeletionBlockedRequeueAfter(d time.Duration) *Builder {
+	b.protectionRequeue = d
+	return b
+}
+
+func (b *Builder) WithClientInstrumentation(i *pmclient.Instrumentation) *Builder {
+	b.instrumentation = i
+	return b
+}
+
 func (b *Builder) WithStaticThenExponentialRateLimiter(opts ...ratelimiter.Option) *Builder {
 	b.rateLimiterOptions = &opts
 	return b
@@ -64,6 +114,43 @@ func (b *Builder) WithInitializer(initializer string) *Builder {
 	return b
 }
 
+func (b *Builder) WithLegacyFinalizers(finalizers ...api.LegacyFinalizer) *Builder {
+	b.legacyFinalizers = append(b.legacyFinalizers, finalizers...)
+	return b
+}
+
+func (b *Builder) WithEventRecorder(recorder events.EventRecorder) *Builder {
+	b.eventRecorder = recorder
+	return b
+}
+
+func (b *Builder) WithWatchdog(w *watchdog.Watchdog) *Builder {
+	b.watchdog = w
+	return b
+}
+
+func (b *Builder) WithSharding(s *sharding.Sharder) *Builder {
+	b.sharder = s
+	return b
+}
+
+func (b *Builder) WithTrigger(t *trigger.Trigger) *Builder {
+	b.trigger = t
+	return b
+}
+
+func (b *Builder) WithPriorityQueue(p *priority.Policy) *Builder {
+	b.queuePolicy = p
+	return b
+}
+
+// WithDebugClaims is only supported by the controller-runtime lifecycle manager, the setup of the multicluster lifecycle
+// manager fails if claims are set
+func (b *Builder) WithDebugClaims(c *filter.Claims) *Builder {
+	b.debugClaims = c
+	return b
+}
+
 func (b *Builder) BuildControllerRuntime(cl client.Client) *controllerruntime.LifecycleManager {
 	lm := controllerruntime.NewLifecycleManager(b.subroutines, b.operatorName, b.controllerName, cl, b.log)
 	if b.withConditionManagement {
@@ -72,12 +159,53 @@ func (b *Builder) BuildControllerRuntime(cl client.Client) *controllerruntime.Li
 	if b.withSpreadingReconciles {
 		lm.WithSpreadingReconciles()
 	}
-	if b.withReadOnly {
+	if b.withReadOnlyDryRun {
+		lm.WithReadOnlyDryRun()
+	} else if b.withReadOnly {
 		lm.WithReadOnly()
 	}
+	if b.withSubroutineReports {
+		lm.WithSubroutineReports(b.reportMinInterval)
+	}
+	if b.withSkipUnchanged {
+		lm.WithSkipUnchangedSubroutines()
+	}
+	if b.protectionRequeue > 0 {
+		lm.WithDeletionBlockedRequeueAfter(b.protectionRequeue)
+	}
+	if b.instrumentation != nil {
+		lm.WithClientInstrumentation(b.instrumentation)
+	}
 	if b.rateLimiterOptions != nil {
 		lm.WithStaticThenExponentialRateLimiter((*b.rateLimiterOptions)...)
 	}
+	if len(b.legacyFinalizers) > 0 {
+		lm.WithLegacyFinalizers(b.legacyFinalizers...)
+	}
+	if b.eventRecorder != nil {
+		lm.WithEventRecorder(b.eventRecorder)
+	}
+	if b.watchdog != nil {
+		lm.WithWatchdog(b.watchdog)
+	}
+	if b.sharder != nil {
+		lm.WithSharding(b.sharder)
+	}
+	if b.trigger != nil {
+		lm.WithTrigger(b.trigger)
+	}
+	if b.queuePolicy != nil {
+		lm.WithPriorityQueue(b.queuePolicy)
+	}
+	if b.debugClaims != nil {
+		lm.WithDebugClaims(b.debugClaims)
+	}
+	if b.terminator != "" {
+		lm.WithTerminator(b.terminator)
+	}
+	if b.initializer != "" {
+		lm.WithInitializer(b.initializer)
+	}
 	return lm
 }
 
@@ -89,12 +217,47 @@ func (b *Builder) BuildMultiCluster(mgr mcmanager.Manager) *multicluster.Lifecyc
 	if b.withSpreadingReconciles {
 		lm.WithSpreadingReconciles()
 	}
-	if b.withReadOnly {
+	if b.withReadOnlyDryRun {
+		lm.WithReadOnlyDryRun()
+	} else if b.withReadOnly {
 		lm.WithReadOnly()
 	}
+	if b.withSubroutineReports {
+		lm.WithSubroutineReports(b.reportMinInterval)
+	}
+	if b.withSkipUnchanged {
+		lm.WithSkipUnchangedSubroutines()
+	}
+	if b.protectionRequeue > 0 {
+		lm.WithDeletionBlockedRequeueAfter(b.protectionRequeue)
+	}
+	if b.instrumentation != nil {
+		lm.WithClientInstrumentation(b.instrumentation)
+	}
 	if b.rateLimiterOptions != nil {
 		lm.WithStaticThenExponentialRateLimiter((*b.rateLimiterOptions)...)
 	}
+	if len(b.legacyFinalizers) > 0 {
+		lm.WithLegacyFinalizers(b.legacyFinalizers...)
+	}
+	if b.eventRecorder != nil {
+		lm.WithEventRecorder(b.eventRecorder)
+	}
+	if b.watchdog != nil {
+		lm.WithWatchdog(b.watchdog)
+	}
+	if b.sharder != nil {
+		lm.WithSharding(b.sharder)
+	}
+	if b.trigger != nil {
+		lm.WithTrigger(b.trigger)
+	}
+	if b.queuePolicy != nil {
+		lm.WithPriorityQueue(b.queuePolicy)
+	}
+	if b.debugClaims != nil {
+		lm.WithDebugClaims(b.debugClaims)
+	}
 	if b.terminator != "" {
 		lm.WithTerminator(b.terminator)
 	}
@@ -6,9 +6,16 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/events"
 	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
 
+	pmclient "github.com/platform-mesh/golang-commons/controller/client"
+	"github.com/platform-mesh/golang-commons/controller/filter"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/priority"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/ratelimiter"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/sharding"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/trigger"
 	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
 	"github.com/platform-mesh/golang-commons/logger"
 )
@@ -60,6 +67,94 @@ func TestBuilder_WithReadOnly(t *testing.T) {
 	}
 }
 
+func TestBuilder_WithSubroutineReports(t *testing.T) {
+	b := NewBuilder("op", "ctrl", nil, &logger.Logger{})
+	b.WithSubroutineReports(time.Minute)
+	if !b.withSubroutineReports {
+		t.Error("WithSubroutineReports should set withSubroutineReports to true")
+	}
+	fakeClient := pmtesting.CreateFakeClient(t, &pmtesting.TestApiObject{})
+	assert.NotNil(t, b.BuildControllerRuntime(fakeClient).ReportManager())
+}
+
+func TestBuilder_WithSkipUnchangedSubroutines(t *testing.T) {
+	b := NewBuilder("op", "ctrl", nil, &logger.Logger{})
+	b.WithSkipUnchangedSubroutines()
+	if !b.withSkipUnchanged {
+		t.Error("WithSkipUnchangedSubroutines should set withSkipUnchanged to true")
+	}
+	fakeClient := pmtesting.CreateFakeClient(t, &pmtesting.TestApiObject{})
+	assert.True(t, b.BuildControllerRuntime(fakeClient).SkipUnchangedSubroutines())
+}
+
+func TestBuilder_WithDeletionBlockedRequeueAfter(t *testing.T) {
+	b := NewBuilder("op", "ctrl", nil, &logger.Logger{})
+	b.WithDeletionBlockedRequeueAfter(time.Minute)
+	assert.Equal(t, time.Minute, b.protectionRequeue)
+	fakeClient := pmtesting.CreateFakeClient(t, &pmtesting.TestApiObject{})
+	assert.Equal(t, time.Minute, b.BuildControllerRuntime(fakeClient).DeletionBlockedRequeueAfter())
+}
+
+func TestBuilder_WithSharding(t *testing.T) {
+	sharder, err := sharding.NewSharder(pmtesting.CreateFakeClient(t), "default", "op", &logger.Logger{}, sharding.WithIdentity("a"))
+	assert.NoError(t, err)
+	b := NewBuilder("op", "ctrl", nil, &logger.Logger{})
+	b.WithSharding(sharder)
+	assert.Equal(t, sharder, b.sharder)
+}
+
+func TestBuilder_WithTrigger(t *testing.T) {
+	tr, err := trigger.NewTrigger(&logger.Logger{}, trigger.WithAuthorizer(trigger.AllowSpiffeIDs("spiffe://cluster.local/ns/default/sa/caller")))
+	assert.NoError(t, err)
+	b := NewBuilder("op", "ctrl", nil, &logger.Logger{})
+	b.WithTrigger(tr)
+	assert.Equal(t, tr, b.trigger)
+	assert.NotNil(t, b.BuildControllerRuntime(pmtesting.CreateFakeClient(t)))
+}
+
+func TestBuilder_WithPriorityQueue(t *testing.T) {
+	policy, err := priority.NewPolicy()
+	assert.NoError(t, err)
+	b := NewBuilder("op", "ctrl", nil, &logger.Logger{})
+	b.WithPriorityQueue(policy)
+	assert.Equal(t, policy, b.queuePolicy)
+	assert.Equal(t, policy, b.BuildControllerRuntime(pmtesting.CreateFakeClient(t)).QueuePolicy())
+}
+
+func TestBuilder_WithDebugClaims(t *testing.T) {
+	claims, err := filter.NewClaims(pmtesting.CreateFakeClient(t), "default", &logger.Logger{})
+	assert.NoError(t, err)
+	b := NewBuilder("op", "ctrl", nil, &logger.Logger{})
+	b.WithDebugClaims(claims)
+	assert.Equal(t, claims, b.debugClaims)
+	assert.NotNil(t, b.BuildControllerRuntime(pmtesting.CreateFakeClient(t)))
+	assert.NotNil(t, b.BuildMultiCluster(nil))
+}
+
+func TestBuilder_WithTerminatorAndInitializer(t *testing.T) {
+	b := NewBuilder("op", "ctrl", nil, &logger.Logger{}).WithTerminator("root:terminator").WithInitializer("root:initializer")
+	lm := b.BuildControllerRuntime(pmtesting.CreateFakeClient(t))
+	assert.Equal(t, "root:terminator", lm.Terminator())
+	assert.Equal(t, "root:initializer", lm.Initializer())
+}
+
+func TestBuilder_WithClientInstrumentation(t *testing.T) {
+	instrumentation, err := pmclient.NewInstrumentation()
+	assert.NoError(t, err)
+	b := NewBuilder("op", "ctrl", nil, &logger.Logger{})
+	b.WithClientInstrumentation(instrumentation)
+	assert.Equal(t, instrumentation, b.instrumentation)
+	assert.Equal(t, instrumentation, b.BuildControllerRuntime(pmtesting.CreateFakeClient(t)).ClientInstrumentation())
+}
+
+func TestBuilder_WithReadOnlyDryRun(t *testing.T) {
+	b := NewBuilder("op", "ctrl", nil, &logger.Logger{}).WithReadOnlyDryRun()
+	assert.True(t, b.withReadOnly)
+	assert.True(t, b.withReadOnlyDryRun)
+	assert.Equal(t, api.Config{OperatorName: "op", ControllerName: "ctrl", ReadOnly: true, ReadOnlyDryRun: true}, b.BuildControllerRuntime(pmtesting.CreateFakeClient(t)).Config())
+	assert.True(t, b.BuildMultiCluster(nil).Config().ReadOnlyDryRun)
+}
+
 func TestBuilder_WithCustomRateLimiter(t *testing.T) {
 	t.Run("With options", func(t *testing.T) {
 		b := NewBuilder("op", "ctrl", nil, &logger.Logger{})
@@ -92,6 +187,26 @@ func TestBuilder_WithCustomRateLimiter(t *testing.T) {
 	})
 }
 
+func TestBuilder_WithLegacyFinalizers(t *testing.T) {
+	recorder := events.NewFakeRecorder(1)
+	b := NewBuilder("op", "ctrl", nil, &logger.Logger{}).
+		WithLegacyFinalizers(api.LegacyFinalizer{Name: "old"}, api.LegacyFinalizer{Name: "renamed", MigrateTo: "new"}).
+		WithEventRecorder(recorder)
+	assert.Len(t, b.legacyFinalizers, 2)
+
+	fakeClient := pmtesting.CreateFakeClient(t, &pmtesting.TestApiObject{})
+	lm := b.BuildControllerRuntime(fakeClient)
+	assert.Equal(t, b.legacyFinalizers, lm.LegacyFinalizers())
+	assert.Equal(t, recorder, lm.EventRecorder())
+
+	cfg := &rest.Config{}
+	mgr, err := mcmanager.New(cfg, pmtesting.NewFakeProvider(cfg), mcmanager.Options{})
+	assert.NoError(t, err)
+	mlm := b.BuildMultiCluster(mgr)
+	assert.Equal(t, b.legacyFinalizers, mlm.LegacyFinalizers())
+	assert.Equal(t, recorder, mlm.EventRecorder())
+}
+
 func TestControllerRuntimeBuilder(t *testing.T) {
 	t.Run("Minimal setup", func(t *testing.T) {
 		b := NewBuilder("op", "ctrl", nil, &logger.Logger{})
@@ -0,0 +1,128 @@
+// Package checkpoint persists the finalization progress of subroutines across reconciles. A subroutine finalizing in
+// several steps records the completed steps or a cursor, so that the next reconcile continues where a failed
+// Finalize stopped instead of starting over. The lifecycle stores the progress in the status of instances
+// implementing api.RuntimeObjectFinalizeCheckpoints and clears it once the finalizer of the subroutine is removed.
+package checkpoint
+
+import (
+	"context"
+	"slices"
+
+	"github.com/platform-mesh/golang-commons/context/keys"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+)
+
+// Checkpoint is the finalization progress of a single subroutine
+type Checkpoint struct {
+	name string
+	// store persists the progress, nil if the instance does not support checkpoints, the progress is then lost after
+	// the current call
+	store api.RuntimeObjectFinalizeCheckpoints
+	local api.SubroutineFinalizeCheckpoint
+}
+
+// New returns the checkpoint of the subroutine, persisted in the status of the instance. Instances not implementing
+// api.RuntimeObjectFinalizeCheckpoints are rejected by lifecycle.ValidateInterfaces for subroutines implementing
+// subroutine.FinalizeCheckpointer.
+func New(instance runtimeobject.RuntimeObject, subroutine string) *Checkpoint {
+	c := &Checkpoint{name: subroutine, local: api.SubroutineFinalizeCheckpoint{Name: subroutine}}
+	if store, ok := instance.(api.RuntimeObjectFinalizeCheckpoints); ok {
+		c.store = store
+	}
+	return c
+}
+
+// SetCheckpointInContext stores the checkpoint in the context, e.g. to pass it to a finalizing subroutine
+func SetCheckpointInContext(ctx context.Context, c *Checkpoint) context.Context {
+	return context.WithValue(ctx, keys.CheckpointCtxKey, c)
+}
+
+// LoadCheckpointFromContext returns the checkpoint stored in the context, or a checkpoint kept in memory if there is
+// none, e.g. when a subroutine is called directly in tests
+func LoadCheckpointFromContext(ctx context.Context) *Checkpoint {
+	if c, ok := ctx.Value(keys.CheckpointCtxKey).(*Checkpoint); ok {
+		return c
+	}
+	return &Checkpoint{}
+}
+
+// StepDone returns whether the step was completed in a previous attempt
+func (c *Checkpoint) StepDone(step string) bool {
+	return slices.Contains(c.get().CompletedSteps, step)
+}
+
+// CompleteStep records the step as completed
+func (c *Checkpoint) CompleteStep(step string) {
+	current := c.get()
+	if slices.Contains(current.CompletedSteps, step) {
+		return
+	}
+	current.CompletedSteps = append(slices.Clone(current.CompletedSteps), step)
+	c.set(current)
+}
+
+// Cursor returns the cursor recorded in a previous attempt, or an empty string
+func (c *Checkpoint) Cursor() string {
+	return c.get().Cursor
+}
+
+// SetCursor records the cursor, e.g. the continue token of a paginated deletion
+func (c *Checkpoint) SetCursor(cursor string) {
+	current := c.get()
+	if current.Cursor == cursor {
+		return
+	}
+	current.Cursor = cursor
+	c.set(current)
+}
+
+// Clear removes the recorded progress
+func (c *Checkpoint) Clear() {
+	if c.store == nil {
+		c.local = api.SubroutineFinalizeCheckpoint{Name: c.name}
+		return
+	}
+	checkpoints := c.store.GetFinalizeCheckpoints()
+	if !slices.ContainsFunc(checkpoints, c.matches) {
+		return
+	}
+	// The checkpoints are copied, as the status might be shared with the copy of the original instance
+	remaining := slices.DeleteFunc(slices.Clone(checkpoints), c.matches)
+	if len(remaining) == 0 {
+		remaining = nil
+	}
+	c.store.SetFinalizeCheckpoints(remaining)
+}
+
+func (c *Checkpoint) matches(checkpoint api.SubroutineFinalizeCheckpoint) bool {
+	return checkpoint.Name == c.name
+}
+
+func (c *Checkpoint) get() api.SubroutineFinalizeCheckpoint {
+	if c.store == nil {
+		return c.local
+	}
+	for _, checkpoint := range c.store.GetFinalizeCheckpoints() {
+		if c.matches(checkpoint) {
+			return checkpoint
+		}
+	}
+	return api.SubroutineFinalizeCheckpoint{Name: c.name}
+}
+
+func (c *Checkpoint) set(current api.SubroutineFinalizeCheckpoint) {
+	if c.store == nil {
+		c.local = current
+		return
+	}
+	checkpoints := slices.Clone(c.store.GetFinalizeCheckpoints())
+	for i := range checkpoints {
+		if c.matches(checkpoints[i]) {
+			checkpoints[i] = current
+			c.store.SetFinalizeCheckpoints(checkpoints)
+			return
+		}
+	}
+	c.store.SetFinalizeCheckpoints(append(checkpoints, current))
+}
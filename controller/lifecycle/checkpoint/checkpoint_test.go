@@ -0,0 +1,90 @@
+package checkpoint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+)
+
+type checkpointingObject struct {
+	unstructured.Unstructured
+	checkpoints []api.SubroutineFinalizeCheckpoint
+}
+
+func (o *checkpointingObject) GetFinalizeCheckpoints() []api.SubroutineFinalizeCheckpoint {
+	return o.checkpoints
+}
+
+func (o *checkpointingObject) SetFinalizeCheckpoints(checkpoints []api.SubroutineFinalizeCheckpoint) {
+	o.checkpoints = checkpoints
+}
+
+func TestCheckpoint(t *testing.T) {
+	t.Run("persists the progress in the status", func(t *testing.T) {
+		// Arrange
+		instance := &checkpointingObject{}
+		instance.checkpoints = []api.SubroutineFinalizeCheckpoint{{Name: "other", Cursor: "token"}}
+		c := New(instance, "fga")
+
+		// Act
+		c.CompleteStep("tuples")
+		c.CompleteStep("tuples")
+		c.SetCursor("next")
+
+		// Assert
+		assert.True(t, c.StepDone("tuples"))
+		assert.False(t, c.StepDone("store"))
+		assert.Equal(t, "next", c.Cursor())
+		assert.Equal(t, []api.SubroutineFinalizeCheckpoint{
+			{Name: "other", Cursor: "token"},
+			{Name: "fga", CompletedSteps: []string{"tuples"}, Cursor: "next"},
+		}, instance.checkpoints)
+	})
+
+	t.Run("clears only the progress of the subroutine", func(t *testing.T) {
+		// Arrange
+		instance := &checkpointingObject{}
+		instance.checkpoints = []api.SubroutineFinalizeCheckpoint{{Name: "other", Cursor: "token"}, {Name: "fga", Cursor: "next"}}
+		c := New(instance, "fga")
+
+		// Act
+		c.Clear()
+
+		// Assert
+		assert.Equal(t, "", c.Cursor())
+		assert.Equal(t, []api.SubroutineFinalizeCheckpoint{{Name: "other", Cursor: "token"}}, instance.checkpoints)
+	})
+
+	t.Run("keeps the progress in memory for instances without checkpoints", func(t *testing.T) {
+		// Arrange
+		c := New(&unstructured.Unstructured{}, "fga")
+
+		// Act
+		c.CompleteStep("tuples")
+
+		// Assert
+		assert.True(t, c.StepDone("tuples"))
+		c.Clear()
+		assert.False(t, c.StepDone("tuples"))
+	})
+
+	t.Run("loads the checkpoint from the context", func(t *testing.T) {
+		// Arrange
+		c := New(&unstructured.Unstructured{}, "fga")
+		c.SetCursor("next")
+
+		// Act
+		loaded := LoadCheckpointFromContext(SetCheckpointInContext(context.Background(), c))
+		fallback := LoadCheckpointFromContext(context.Background())
+
+		// Assert
+		assert.Equal(t, "next", loaded.Cursor())
+		assert.Equal(t, "", fallback.Cursor())
+		fallback.CompleteStep("tuples")
+		assert.True(t, fallback.StepDone("tuples"))
+	})
+}
@@ -0,0 +1,72 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func TestReconcileFinalizeCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New().Logger
+
+	newInstance := func() *pmtesting.ImplementFinalizeCheckpoints {
+		return &pmtesting.ImplementFinalizeCheckpoints{TestApiObject: pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{
+			Name: nName.Name, Namespace: nName.Namespace, DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			Finalizers: []string{pmtesting.CheckpointingSubroutineFinalizer, "other"},
+		}}}
+	}
+
+	t.Run("continues a failed finalization and clears the checkpoint", func(t *testing.T) {
+		// Arrange
+		instance := newInstance()
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		s := &pmtesting.CheckpointingSubroutine{Steps: []string{"a", "b", "c"}, FailAt: "b", Fail: true}
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{s}}
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+		require.Error(t, err)
+		failed := newInstance()
+		require.NoError(t, fakeClient.Get(ctx, nName, failed))
+		s.Fail = false
+		_, err = Reconcile(ctx, nName, instance, fakeClient, mgr)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, []api.SubroutineFinalizeCheckpoint{{Name: "checkpointing", CompletedSteps: []string{"a"}}}, failed.Status.FinalizeCheckpoints)
+		assert.Equal(t, []string{"a", "b", "c"}, s.Executed)
+		finalized := newInstance()
+		require.NoError(t, fakeClient.Get(ctx, nName, finalized))
+		assert.Equal(t, []string{"other"}, finalized.GetFinalizers())
+		assert.Empty(t, finalized.Status.FinalizeCheckpoints)
+	})
+
+	t.Run("keeps the checkpoint in read-only mode", func(t *testing.T) {
+		// Arrange
+		instance := newInstance()
+		instance.Status.FinalizeCheckpoints = []api.SubroutineFinalizeCheckpoint{{Name: "checkpointing", CompletedSteps: []string{"a"}}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		s := &pmtesting.CheckpointingSubroutine{Steps: []string{"a", "b"}}
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{s}}).WithReadOnly(false)
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b"}, s.Executed)
+		assert.Equal(t, []api.SubroutineFinalizeCheckpoint{{Name: "checkpointing", CompletedSteps: []string{"a", "b"}}}, instance.Status.FinalizeCheckpoints)
+	})
+}
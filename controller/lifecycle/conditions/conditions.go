@@ -1,18 +1,22 @@
 package conditions
 
 import (
+	"errors"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	pmerrors "github.com/platform-mesh/golang-commons/controller/errors"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
 	"github.com/platform-mesh/golang-commons/logger"
 )
 
 const (
 	ConditionReady = "Ready"
+	// ConditionDeletionBlocked is set while the finalization of an instance in deletion is blocked by deletion protection
+	ConditionDeletionBlocked = "DeletionBlocked"
 
 	messageResourceReady      = "The resource is ready"
 	messageResourceNotReady   = "The resource is not ready"
@@ -21,6 +25,8 @@ const (
 	reasonComplete   = "Complete"
 	reasonProcessing = "Processing"
 	reasonError      = "Error"
+	// ReasonPanic is the reason of subroutine conditions after a recovered panic of the subroutine
+	ReasonPanic = "Panic"
 
 	subroutineReadyConditionFormatString    = "%s_Ready"
 	subroutineFinalizeConditionFormatString = "%s_Finalize"
@@ -28,6 +34,7 @@ const (
 	subroutineMessageProcessingFormatString = "The %s is processing"
 	subroutineMessageCompleteFormatString   = "The %s is complete"
 	subroutineMessageErrorFormatString      = "The %s has an error: %s"
+	subroutineMessagePanicFormatString      = "The %s panicked: %v"
 )
 
 type ConditionManager struct{}
@@ -109,8 +116,14 @@ func (c *ConditionManager) SetSubroutineCondition(conditions *[]metav1.Condition
 	if subroutineErr != nil {
 		sErr = subroutineErr
 	}
-	changed := meta.SetStatusCondition(conditions,
-		metav1.Condition{Type: conditionName, Status: metav1.ConditionFalse, Message: fmt.Sprintf(subroutineMessageErrorFormatString, conditionMessage, sErr), Reason: reasonError, ObservedGeneration: observedGeneration})
+	condition := metav1.Condition{Type: conditionName, Status: metav1.ConditionFalse, Message: fmt.Sprintf(subroutineMessageErrorFormatString, conditionMessage, sErr), Reason: reasonError, ObservedGeneration: observedGeneration}
+	var panicErr *pmerrors.PanicError
+	if errors.As(subroutineErr, &panicErr) {
+		// the stack is logged and reported to Sentry, but too large for the condition
+		condition.Message = fmt.Sprintf(subroutineMessagePanicFormatString, conditionMessage, panicErr.Value)
+		condition.Reason = ReasonPanic
+	}
+	changed := meta.SetStatusCondition(conditions, condition)
 	if changed {
 		log.Info().Str("type", conditionName).Msg("updated condition")
 	}
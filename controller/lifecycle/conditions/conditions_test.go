@@ -11,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 
+	pmerrors "github.com/platform-mesh/golang-commons/controller/errors"
 	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
 	"github.com/platform-mesh/golang-commons/logger"
 )
@@ -209,6 +210,24 @@ func TestSubroutineCondition(t *testing.T) {
 		assert.Equal(t, metav1.ConditionFalse, condition[0].Status)
 	})
 
+	// Add a test case to set a subroutine condition to the panic reason if it panicked
+	t.Run("TestSetSubroutineConditionPanic", func(t *testing.T) {
+		// Given
+		condition := []metav1.Condition{}
+		cm := NewConditionManager()
+		subroutine := pmtesting.ChangeStatusSubroutine{}
+		panicErr := &pmerrors.PanicError{Subroutine: subroutine.GetName(), Value: "boom", Stack: []byte("stack")}
+
+		// When
+		cm.SetSubroutineCondition(&condition, 0, subroutine, controllerruntime.Result{}, panicErr, false, log)
+
+		// Then
+		assert.Equal(t, 1, len(condition))
+		assert.Equal(t, metav1.ConditionFalse, condition[0].Status)
+		assert.Equal(t, ReasonPanic, condition[0].Reason)
+		assert.Equal(t, "The subroutine panicked: boom", condition[0].Message)
+	})
+
 	// Add a test case to set a subroutine condition for isFinalize true
 	t.Run("TestSetSubroutineFinalizeConditionReady", func(t *testing.T) {
 		// Given
@@ -4,24 +4,35 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
 
+	pmclient "github.com/platform-mesh/golang-commons/controller/client"
 	"github.com/platform-mesh/golang-commons/controller/filter"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/conditions"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/priority"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/ratelimiter"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/report"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/sharding"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/spread"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/trigger"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/watch"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/watchdog"
 	"github.com/platform-mesh/golang-commons/logger"
 )
 
@@ -32,8 +43,21 @@ type LifecycleManager struct {
 	subroutines        []subroutine.Subroutine
 	spreader           *spread.Spreader
 	conditionsManager  *conditions.ConditionManager
+	reportManager      *report.ReportManager
+	skipUnchanged      bool
+	protectionRequeue  time.Duration
+	instrumentation    *pmclient.Instrumentation
+	watchdog           *watchdog.Watchdog
+	sharder            *sharding.Sharder
+	trigger            *trigger.Trigger
+	queuePolicy        *priority.Policy
+	debugClaims        *filter.Claims
 	prepareContextFunc api.PrepareContextFunc
+	legacyFinalizers   []api.LegacyFinalizer
+	eventRecorder      events.EventRecorder
 	rateLimiter        workqueue.TypedRateLimiter[reconcile.Request]
+	terminator         string
+	initializer        string
 }
 
 func NewLifecycleManager(subroutines []subroutine.Subroutine, operatorName string, controllerName string, client client.Client, log *logger.Logger) *LifecycleManager {
@@ -75,7 +99,63 @@ func (l *LifecycleManager) Spreader() api.SpreadManager {
 	}
 	return l.spreader
 }
+func (l *LifecycleManager) ReportManager() api.ReportManager {
+	// it is important to return nil instead of a nil pointer to the interface to avoid misbehaving nil checks
+	if l.reportManager == nil {
+		return nil
+	}
+	return l.reportManager
+}
+func (l *LifecycleManager) SkipUnchangedSubroutines() bool {
+	return l.skipUnchanged
+}
+func (l *LifecycleManager) DeletionBlockedRequeueAfter() time.Duration {
+	return l.protectionRequeue
+}
+func (l *LifecycleManager) ClientInstrumentation() api.ClientInstrumentation {
+	// it is important to return nil instead of a nil pointer to the interface to avoid misbehaving nil checks
+	if l.instrumentation == nil {
+		return nil
+	}
+	return l.instrumentation
+}
+func (l *LifecycleManager) ReconcileTracker() api.ReconcileTracker {
+	// it is important to return nil instead of a nil pointer to the interface to avoid misbehaving nil checks
+	if l.watchdog == nil {
+		return nil
+	}
+	return l.watchdog
+}
+func (l *LifecycleManager) QueuePolicy() api.QueuePolicy {
+	// it is important to return nil instead of a nil pointer to the interface to avoid misbehaving nil checks
+	if l.queuePolicy == nil {
+		return nil
+	}
+	return l.queuePolicy
+}
+func (l *LifecycleManager) LegacyFinalizers() []api.LegacyFinalizer {
+	return l.legacyFinalizers
+}
+func (l *LifecycleManager) EventRecorder() events.EventRecorder {
+	return l.eventRecorder
+}
+
+func (l *LifecycleManager) Terminator() string {
+	return l.terminator
+}
+
+func (l *LifecycleManager) Initializer() string {
+	return l.initializer
+}
 func (l *LifecycleManager) Reconcile(ctx context.Context, req ctrl.Request, instance runtimeobject.RuntimeObject) (ctrl.Result, error) {
+	if l.sharder != nil && !l.sharder.Owns(req.NamespacedName.String()) {
+		l.log.Debug().Str("name", req.Name).Str("namespace", req.Namespace).Msg("skipping reconcile, instance is owned by another shard")
+		return ctrl.Result{}, nil
+	}
+	if l.debugClaims != nil && !l.debugClaims.Accepts(req.NamespacedName) {
+		l.log.Debug().Str("name", req.Name).Str("namespace", req.Namespace).Msg("skipping reconcile, instance is not claimed by this controller")
+		return ctrl.Result{}, nil
+	}
 	return lifecycle.Reconcile(ctx, req.NamespacedName, instance, l.client, l)
 }
 
@@ -83,9 +163,15 @@ func (l *LifecycleManager) SetupWithManagerBuilder(mgr ctrl.Manager, maxReconcil
 	if err := lifecycle.ValidateInterfaces(instance, log, l); err != nil {
 		return nil, err
 	}
+	if _, err := lifecycle.FinalizeOrder(l.Subroutines()); err != nil {
+		return nil, err
+	}
 
-	if (l.ConditionsManager() != nil || l.Spreader() != nil) && l.Config().ReadOnly {
-		return nil, fmt.Errorf("cannot use conditions or spread reconciles in read-only mode")
+	if (l.ConditionsManager() != nil || l.Spreader() != nil || l.ReportManager() != nil) && l.Config().ReadOnly {
+		return nil, fmt.Errorf("cannot use conditions, spread reconciles or subroutine reports in read-only mode")
+	}
+	if l.Config().ReadOnly {
+		log.Warn().Msg("read-only mode only guards the clients subroutines load with client.LoadClientFromContext, clients injected into subroutines can still write")
 	}
 
 	eventPredicates = append([]predicate.Predicate{filter.DebugResourcesBehaviourPredicate(debugLabelValue)}, eventPredicates...)
@@ -96,12 +182,69 @@ func (l *LifecycleManager) SetupWithManagerBuilder(mgr ctrl.Manager, maxReconcil
 	if l.rateLimiter != nil {
 		opts.RateLimiter = l.rateLimiter
 	}
+	if l.queuePolicy != nil {
+		opts.UsePriorityQueue = ptr.To(true)
+	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	var instancePredicates []predicate.Predicate
+	if l.sharder != nil {
+		instancePredicates = append(instancePredicates, l.sharder.Predicate())
+	}
+	if l.debugClaims != nil {
+		instancePredicates = append(instancePredicates, l.debugClaims.Predicate())
+	}
+
+	b := ctrl.NewControllerManagedBy(mgr).
 		Named(reconcilerName).
-		For(instance).
+		For(instance, builder.WithPredicates(instancePredicates...)).
 		WithOptions(opts).
-		WithEventFilter(predicate.And(eventPredicates...)), nil
+		WithEventFilter(predicate.And(eventPredicates...))
+
+	if l.queuePolicy != nil {
+		b.Watches(instance, l.queuePolicy.Handler(l.config.ControllerName), builder.WithPredicates(instancePredicates...))
+	}
+
+	if err := l.setupWatches(mgr, b, instance, log); err != nil {
+		return nil, err
+	}
+	if l.sharder != nil {
+		src, err := l.sharder.ResyncSource(mgr.GetClient(), mgr.GetScheme(), instance)
+		if err != nil {
+			return nil, err
+		}
+		b.WatchesRawSource(src)
+	}
+	if l.trigger != nil {
+		b.WatchesRawSource(l.trigger.Source())
+	}
+	if l.debugClaims != nil {
+		b.WatchesRawSource(l.debugClaims.Source())
+	}
+	return b, nil
+}
+
+// setupWatches registers the watches and field indexes declared by subroutines implementing subroutine.Watcher
+func (l *LifecycleManager) setupWatches(mgr ctrl.Manager, b *builder.Builder, instance runtimeobject.RuntimeObject, log *logger.Logger) error {
+	watches, err := lifecycle.CollectWatches(l)
+	if err != nil {
+		return err
+	}
+	if err := lifecycle.IndexWatchedFields(context.Background(), mgr.GetFieldIndexer(), instance, watches); err != nil {
+		return err
+	}
+
+	for _, w := range watches {
+		if w.Mapping == watch.MapByOwnerReference {
+			b.Owns(w.Object, builder.WithPredicates(w.Predicates...))
+			continue
+		}
+		mapFunc, err := w.MapFunc(mgr.GetClient(), mgr.GetScheme(), instance, log)
+		if err != nil {
+			return err
+		}
+		b.Watches(w.Object, handler.EnqueueRequestsFromMapFunc(mapFunc), builder.WithPredicates(w.Predicates...))
+	}
+	return nil
 }
 func (l *LifecycleManager) SetupWithManager(mgr ctrl.Manager, maxReconciles int, reconcilerName string, instance runtimeobject.RuntimeObject, debugLabelValue string, r reconcile.Reconciler, log *logger.Logger, eventPredicates ...predicate.Predicate) error {
 	b, err := l.SetupWithManagerBuilder(mgr, maxReconciles, reconcilerName, instance, debugLabelValue, log, eventPredicates...)
@@ -121,12 +264,24 @@ func (l *LifecycleManager) WithPrepareContextFunc(prepareFunction api.PrepareCon
 }
 
 // WithReadOnly allows to set the controller to read-only mode
-// In read-only mode, the controller will not update the status of the instance
+// In read-only mode, the controller will not update the status of the instance and writes of subroutines made with
+// the client from the context are rejected
+// Clients injected into subroutines at construction are not guarded, subroutines have to load their client with
+// client.LoadClientFromContext or be constructed with a client.NewReadOnlyClient
 func (l *LifecycleManager) WithReadOnly() *LifecycleManager {
 	l.config.ReadOnly = true
 	return l
 }
 
+// WithReadOnlyDryRun allows to set the controller to read-only mode, sending the writes of subroutines
+// made with the client from the context as dry run instead of rejecting them
+// Clients injected into subroutines at construction are not guarded, see WithReadOnly
+func (l *LifecycleManager) WithReadOnlyDryRun() *LifecycleManager {
+	l.config.ReadOnly = true
+	l.config.ReadOnlyDryRun = true
+	return l
+}
+
 // WithSpreadingReconciles sets the LifecycleManager to spread out the reconciles
 func (l *LifecycleManager) WithSpreadingReconciles() *LifecycleManager {
 	l.spreader = spread.NewSpreader()
@@ -146,3 +301,92 @@ func (l *LifecycleManager) WithStaticThenExponentialRateLimiter(opts ...ratelimi
 	l.rateLimiter = rateLimiter
 	return l
 }
+
+func (l *LifecycleManager) WithTerminator(terminator string) *LifecycleManager {
+	l.terminator = terminator
+	return l
+}
+
+func (l *LifecycleManager) WithInitializer(initializer string) *LifecycleManager {
+	l.initializer = initializer
+	return l
+}
+
+// WithLegacyFinalizers allows to configure finalizers which are no longer owned by any subroutine
+// These finalizers are removed from instances or migrated to the finalizer configured in MigrateTo
+func (l *LifecycleManager) WithLegacyFinalizers(finalizers ...api.LegacyFinalizer) *LifecycleManager {
+	l.legacyFinalizers = append(l.legacyFinalizers, finalizers...)
+	return l
+}
+
+// WithEventRecorder allows to set a recorder used to emit events for the reconciled instances
+func (l *LifecycleManager) WithEventRecorder(recorder events.EventRecorder) *LifecycleManager {
+	l.eventRecorder = recorder
+	return l
+}
+
+// WithSubroutineReports allows to persist a report per subroutine in the status of the instance
+// Runs with an unchanged outcome are recorded at most once per minInterval to avoid status write storms
+func (l *LifecycleManager) WithSubroutineReports(minInterval time.Duration) *LifecycleManager {
+	l.reportManager = report.NewReportManager(minInterval)
+	return l
+}
+
+// WithClientInstrumentation allows to pass a client instrumented with spans, metrics and slow call logs to the
+// subroutines via the context, labelled with the subroutine name. Subroutines load it with client.LoadClientFromContext
+func (l *LifecycleManager) WithClientInstrumentation(i *pmclient.Instrumentation) *LifecycleManager {
+	l.instrumentation = i
+	return l
+}
+
+// WithSkipUnchangedSubroutines allows to skip subroutines implementing subroutine.InputHasher as long as their input
+// hash and the generation are unchanged since their last successful run. The refresh label forces processing.
+func (l *LifecycleManager) WithSkipUnchangedSubroutines() *LifecycleManager {
+	l.skipUnchanged = true
+	return l
+}
+
+// WithDeletionBlockedRequeueAfter allows to configure the delay after which a deletion blocked by deletion protection
+// is checked again, defaults to lifecycle.DefaultDeletionBlockedRequeueAfter
+func (l *LifecycleManager) WithDeletionBlockedRequeueAfter(d time.Duration) *LifecycleManager {
+	l.protectionRequeue = d
+	return l
+}
+
+// WithSharding allows to partition the instances among the replicas of the shard group of the sharder
+// Events of instances owned by other replicas are filtered and the instances of a replica are resynced on a rebalance
+// The sharder needs to be added to the manager separately and leader election should be disabled
+func (l *LifecycleManager) WithSharding(s *sharding.Sharder) *LifecycleManager {
+	l.sharder = s
+	return l
+}
+
+// WithDebugClaims allows to route instances between production and developer controllers using debug claims
+// Instances not accepted by the claims are skipped and instances handed over by a claim change are reconciled
+// The claims need to be added to the manager separately
+func (l *LifecycleManager) WithDebugClaims(c *filter.Claims) *LifecycleManager {
+	l.debugClaims = c
+	return l
+}
+
+// WithWatchdog allows to track in-flight reconciles with the given watchdog to detect reconciles stuck in a subroutine
+// The watchdog needs to be added to the manager and its Checker registered as health check separately
+func (l *LifecycleManager) WithWatchdog(w *watchdog.Watchdog) *LifecycleManager {
+	l.watchdog = w
+	return l
+}
+
+// WithTrigger allows to enqueue reconciles on demand with requests to the HTTP endpoint of the trigger
+// The trigger needs to be added to the manager separately
+func (l *LifecycleManager) WithTrigger(t *trigger.Trigger) *LifecycleManager {
+	l.trigger = t
+	return l
+}
+
+// WithPriorityQueue allows to prioritize reconciles with the policy in controller-runtime's priority queue
+// Generation changes, deletions and refresh requests are reconciled before other events and spread-driven resyncs
+// are reconciled last
+func (l *LifecycleManager) WithPriorityQueue(p *priority.Policy) *LifecycleManager {
+	l.queuePolicy = p
+	return l
+}
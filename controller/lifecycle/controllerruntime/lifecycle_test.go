@@ -7,17 +7,31 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
+	pmerrors "github.com/platform-mesh/golang-commons/controller/errors"
+	"github.com/platform-mesh/golang-commons/controller/filter"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/conditions"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/priority"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/ratelimiter"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/sharding"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/trigger"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/watch"
 	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/controller/testSupport/fault"
 	"github.com/platform-mesh/golang-commons/errors"
 	"github.com/platform-mesh/golang-commons/logger/testlogger"
 )
@@ -50,6 +64,226 @@ func TestLifecycle(t *testing.T) {
 		// Assert
 		assert.NoError(t, err)
 	})
+	t.Run("Test Lifecycle setupWithManager /w subroutine watches and expecting no error", func(t *testing.T) {
+		// Arrange
+		instance := &corev1.Namespace{}
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+		m, err := manager.New(&rest.Config{}, manager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+
+		log := testlogger.New()
+		lm := NewLifecycleManager([]subroutine.Subroutine{pmtesting.WatchingSubroutine{WatchList: []watch.Watch{
+			watch.Owns(&corev1.ConfigMap{}),
+			watch.ByLabel(&corev1.Secret{}, "owner", ""),
+		}}}, "test-operator", "test-controller", nil, log.Logger)
+		tr := &testReconciler{lifecycleManager: lm}
+
+		// Act
+		err = lm.SetupWithManager(m, 0, "testReconcilerWithWatches", instance, "test", tr, log.Logger)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+	t.Run("Test Lifecycle setupWithManager /w sharding and expecting no error", func(t *testing.T) {
+		// Arrange
+		instance := &corev1.Namespace{}
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+		m, err := manager.New(&rest.Config{}, manager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+
+		log := testlogger.New()
+		sharder, err := sharding.NewSharder(fake.NewClientBuilder().Build(), "default", "test-operator", log.Logger, sharding.WithIdentity("a"))
+		assert.NoError(t, err)
+		lm := NewLifecycleManager([]subroutine.Subroutine{}, "test-operator", "test-controller", nil, log.Logger).WithSharding(sharder)
+		tr := &testReconciler{lifecycleManager: lm}
+
+		// Act
+		err = lm.SetupWithManager(m, 0, "testReconcilerWithSharding", instance, "test", tr, log.Logger)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+	t.Run("Test Lifecycle reconcile /w sharding skips instances of other shards", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+
+		lm, log := createLifecycleManager([]subroutine.Subroutine{pmtesting.ChangeStatusSubroutine{Client: fakeClient}}, fakeClient)
+		sharder, err := sharding.NewSharder(fake.NewClientBuilder().Build(), "default", "test-operator", log.Logger, sharding.WithIdentity("a"))
+		assert.NoError(t, err)
+		lm.WithSharding(sharder)
+		tr := &testReconciler{lifecycleManager: lm}
+
+		// Act
+		result, err := tr.Reconcile(ctx, controllerruntime.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, controllerruntime.Result{}, result)
+		assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(instance), instance))
+		assert.Empty(t, instance.Status.Some)
+		assert.Empty(t, instance.Finalizers)
+	})
+	t.Run("Test Lifecycle reconcile /w panicking subroutine sets the panic condition", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		instance := &pmtesting.ImplementConditions{TestApiObject: pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		injector, err := fault.NewInjector(fault.WithRules(fault.Rule{Fault: fault.Panic("boom")}))
+		assert.NoError(t, err)
+
+		lm, _ := createLifecycleManager([]subroutine.Subroutine{injector.Subroutine(pmtesting.ChangeStatusSubroutine{Client: fakeClient})}, fakeClient)
+		lm.WithConditionManagement()
+
+		// Act
+		_, err = lm.Reconcile(ctx, controllerruntime.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}, &pmtesting.ImplementConditions{})
+
+		// Assert
+		assert.True(t, pmerrors.IsPanic(err))
+		assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(instance), instance))
+		condition := meta.FindStatusCondition(instance.Status.Conditions, "changeStatus_Ready")
+		if assert.NotNil(t, condition) {
+			assert.Equal(t, conditions.ReasonPanic, condition.Reason)
+			assert.Equal(t, "The subroutine panicked: boom", condition.Message)
+		}
+		assert.True(t, meta.IsStatusConditionFalse(instance.Status.Conditions, conditions.ConditionReady))
+	})
+	t.Run("Test Lifecycle reconcile /w debug claims skips claimed instances", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, UID: "uid"}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		leaseClient := fake.NewClientBuilder().Build()
+
+		lm, log := createLifecycleManager([]subroutine.Subroutine{pmtesting.ChangeStatusSubroutine{Client: fakeClient}}, fakeClient)
+		developer, err := filter.NewClaims(leaseClient, "default", log.Logger, filter.WithDeveloper("jane"))
+		assert.NoError(t, err)
+		assert.NoError(t, developer.Claim(ctx, instance))
+		production, err := filter.NewClaims(leaseClient, "default", log.Logger)
+		assert.NoError(t, err)
+		assert.NoError(t, production.Refresh(ctx))
+		lm.WithDebugClaims(production)
+		tr := &testReconciler{lifecycleManager: lm}
+
+		// Act
+		result, err := tr.Reconcile(ctx, controllerruntime.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, controllerruntime.Result{}, result)
+		assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(instance), instance))
+		assert.Empty(t, instance.Status.Some)
+	})
+	t.Run("Test Lifecycle setupWithManager /w debug claims and expecting no error", func(t *testing.T) {
+		// Arrange
+		instance := &corev1.Namespace{}
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+		m, err := manager.New(&rest.Config{}, manager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+
+		log := testlogger.New()
+		claims, err := filter.NewClaims(fake.NewClientBuilder().Build(), "default", log.Logger)
+		assert.NoError(t, err)
+		lm := NewLifecycleManager([]subroutine.Subroutine{}, "test-operator", "test-controller", nil, log.Logger).WithDebugClaims(claims)
+		tr := &testReconciler{lifecycleManager: lm}
+
+		// Act
+		err = lm.SetupWithManager(m, 0, "testReconcilerWithDebugClaims", instance, "", tr, log.Logger)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+	t.Run("Test Lifecycle setupWithManager /w trigger and expecting no error", func(t *testing.T) {
+		// Arrange
+		instance := &corev1.Namespace{}
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+		m, err := manager.New(&rest.Config{}, manager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+
+		log := testlogger.New()
+		tr, err := trigger.NewTrigger(log.Logger, trigger.WithAuthorizer(trigger.AllowSpiffeIDs("spiffe://cluster.local/ns/default/sa/caller")))
+		assert.NoError(t, err)
+		lm := NewLifecycleManager([]subroutine.Subroutine{}, "test-operator", "test-controller", nil, log.Logger).WithTrigger(tr)
+		r := &testReconciler{lifecycleManager: lm}
+
+		// Act
+		err = lm.SetupWithManager(m, 0, "testReconcilerWithTrigger", instance, "", r, log.Logger)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+	t.Run("Test Lifecycle setupWithManager /w priority queue and expecting no error", func(t *testing.T) {
+		// Arrange
+		instance := &corev1.Namespace{}
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+		m, err := manager.New(&rest.Config{}, manager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+
+		log := testlogger.New()
+		policy, err := priority.NewPolicy()
+		assert.NoError(t, err)
+		lm := NewLifecycleManager([]subroutine.Subroutine{}, "test-operator", "test-controller", nil, log.Logger).WithPriorityQueue(policy)
+		r := &testReconciler{lifecycleManager: lm}
+
+		// Act
+		err = lm.SetupWithManager(m, 0, "testReconcilerWithPriorityQueue", instance, "", r, log.Logger)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, policy, lm.QueuePolicy())
+	})
+	t.Run("Test Lifecycle setupWithManager /w invalid subroutine watch and expecting a error", func(t *testing.T) {
+		// Arrange
+		instance := &corev1.Namespace{}
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+		m, err := manager.New(&rest.Config{}, manager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+
+		log := testlogger.New()
+		lm := NewLifecycleManager([]subroutine.Subroutine{pmtesting.WatchingSubroutine{WatchList: []watch.Watch{
+			watch.ByIndexedField(&corev1.ServiceAccount{}, "serviceAccount", nil, nil),
+		}}}, "test-operator", "test-controller", nil, log.Logger)
+
+		// Act
+		_, err = lm.SetupWithManagerBuilder(m, 0, "testReconcilerWithInvalidWatches", instance, "test", log.Logger)
+
+		// Assert
+		assert.Error(t, err)
+	})
+	t.Run("Test Lifecycle setupWithManager /w finalize order cycle and expecting a error", func(t *testing.T) {
+		// Arrange
+		instance := &corev1.Namespace{}
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+		m, err := manager.New(&rest.Config{}, manager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+
+		log := testlogger.New()
+		lm := NewLifecycleManager([]subroutine.Subroutine{
+			pmtesting.OrderedSubroutine{Name: "a", After: []string{"b"}},
+			pmtesting.OrderedSubroutine{Name: "b", After: []string{"a"}},
+		}, "test-operator", "test-controller", nil, log.Logger)
+
+		// Act
+		_, err = lm.SetupWithManagerBuilder(m, 0, "testReconcilerWithFinalizeCycle", instance, "test", log.Logger)
+
+		// Assert
+		assert.EqualError(t, err, "the finalize order of the subroutines b, a has a cycle")
+	})
 	t.Run("Test Lifecycle setupWithManager /w spread and expecting a error", func(t *testing.T) {
 		// Arrange
 		instance := &pmtesting.NotImplementingSpreadReconciles{}
@@ -0,0 +1,97 @@
+package lifecycle
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+)
+
+// FinalizeOrder returns the subroutines in the order they are finalized. By default this is the reverse process
+// order. Subroutines implementing subroutine.FinalizePrioritizer are moved according to their priority, and
+// subroutines implementing subroutine.FinalizeDependent are finalized after their dependencies. An error is returned
+// for dependencies on unknown subroutines and for cycles.
+func FinalizeOrder(subroutines []subroutine.Subroutine) ([]subroutine.Subroutine, error) {
+	reversed := slices.Clone(subroutines)
+	slices.Reverse(reversed)
+
+	index := make(map[string][]int, len(reversed))
+	for i, s := range reversed {
+		index[s.GetName()] = append(index[s.GetName()], i)
+	}
+
+	// dependents[i] are the subroutines finalized after reversed[i], pending[i] the number of unfinalized dependencies
+	dependents := make([][]int, len(reversed))
+	pending := make([]int, len(reversed))
+	priorities := make([]int, len(reversed))
+	for i, s := range reversed {
+		if p, ok := s.(subroutine.FinalizePrioritizer); ok {
+			priorities[i] = p.FinalizePriority()
+		}
+		d, ok := s.(subroutine.FinalizeDependent)
+		if !ok {
+			continue
+		}
+		for _, name := range slices.Compact(slices.Sorted(slices.Values(d.FinalizeAfter()))) {
+			switch len(index[name]) {
+			case 0:
+				return nil, fmt.Errorf("subroutine %s is finalized after unknown subroutine %s", s.GetName(), name)
+			case 1:
+			default:
+				return nil, fmt.Errorf("subroutine %s is finalized after subroutine %s, whose name is not unique", s.GetName(), name)
+			}
+			dependency := index[name][0]
+			dependents[dependency] = append(dependents[dependency], i)
+			pending[i]++
+		}
+	}
+
+	ordered := make([]subroutine.Subroutine, 0, len(reversed))
+	done := make([]bool, len(reversed))
+	for len(ordered) < len(reversed) {
+		// pick the subroutine without pending dependencies with the highest priority, preferring the reverse process order
+		next := -1
+		for i := range reversed {
+			if done[i] || pending[i] > 0 {
+				continue
+			}
+			if next == -1 || priorities[i] > priorities[next] {
+				next = i
+			}
+		}
+		if next == -1 {
+			var cycle []string
+			for i, s := range reversed {
+				if !done[i] {
+					cycle = append(cycle, s.GetName())
+				}
+			}
+			return nil, fmt.Errorf("the finalize order of the subroutines %s has a cycle", strings.Join(cycle, ", "))
+		}
+		done[next] = true
+		ordered = append(ordered, reversed[next])
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+		}
+	}
+	return ordered, nil
+}
+
+// pendingFinalizeDependency returns the name of a dependency of the subroutine whose finalizer is still on the
+// instance, e.g. because its Finalize requested a requeue. The subroutine must not be finalized before it.
+func pendingFinalizeDependency(instance runtimeobject.RuntimeObject, s subroutine.Subroutine, subroutines []subroutine.Subroutine) (string, bool) {
+	d, ok := s.(subroutine.FinalizeDependent)
+	if !ok {
+		return "", false
+	}
+	for _, name := range d.FinalizeAfter() {
+		for _, dependency := range subroutines {
+			if dependency.GetName() == name && containsFinalizer(instance, dependency.Finalizers(instance)) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,164 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/controller/testSupport/fault"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func names(subroutines []subroutine.Subroutine) []string {
+	var result []string
+	for _, s := range subroutines {
+		result = append(result, s.GetName())
+	}
+	return result
+}
+
+func TestFinalizeOrder(t *testing.T) {
+	tests := []struct {
+		name        string
+		subroutines []subroutine.Subroutine
+		expected    []string
+		err         string
+	}{
+		{
+			name:        "reverses the process order by default",
+			subroutines: []subroutine.Subroutine{pmtesting.OrderedSubroutine{Name: "a"}, pmtesting.OrderedSubroutine{Name: "b"}, pmtesting.OrderedSubroutine{Name: "c"}},
+			expected:    []string{"c", "b", "a"},
+		},
+		{
+			name:        "finalizes higher priorities first",
+			subroutines: []subroutine.Subroutine{pmtesting.OrderedSubroutine{Name: "a", Priority: 10}, pmtesting.OrderedSubroutine{Name: "b"}, pmtesting.OrderedSubroutine{Name: "c", Priority: -1}},
+			expected:    []string{"a", "b", "c"},
+		},
+		{
+			name: "finalizes after dependencies",
+			subroutines: []subroutine.Subroutine{
+				pmtesting.OrderedSubroutine{Name: "tenant", After: []string{"children", "fga"}},
+				pmtesting.OrderedSubroutine{Name: "fga"},
+				pmtesting.OrderedSubroutine{Name: "children", After: []string{"fga"}},
+			},
+			expected: []string{"fga", "children", "tenant"},
+		},
+		{
+			name: "prefers dependencies over priorities",
+			subroutines: []subroutine.Subroutine{
+				pmtesting.OrderedSubroutine{Name: "a", Priority: 10, After: []string{"b"}},
+				pmtesting.OrderedSubroutine{Name: "b"},
+				pmtesting.OrderedSubroutine{Name: "c", Priority: 5},
+			},
+			expected: []string{"c", "b", "a"},
+		},
+		{
+			name:        "rejects unknown dependencies",
+			subroutines: []subroutine.Subroutine{pmtesting.OrderedSubroutine{Name: "a", After: []string{"missing"}}},
+			err:         "subroutine a is finalized after unknown subroutine missing",
+		},
+		{
+			name: "rejects cycles",
+			subroutines: []subroutine.Subroutine{
+				pmtesting.OrderedSubroutine{Name: "a", After: []string{"c"}},
+				pmtesting.OrderedSubroutine{Name: "b", After: []string{"a"}},
+				pmtesting.OrderedSubroutine{Name: "c", After: []string{"b"}},
+				pmtesting.OrderedSubroutine{Name: "d"},
+			},
+			err: "the finalize order of the subroutines c, b, a has a cycle",
+		},
+		{
+			name:        "rejects dependencies on duplicate names",
+			subroutines: []subroutine.Subroutine{pmtesting.OrderedSubroutine{Name: "a"}, pmtesting.OrderedSubroutine{Name: "a"}, pmtesting.OrderedSubroutine{Name: "b", After: []string{"a"}}},
+			err:         "subroutine b is finalized after subroutine a, whose name is not unique",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ordered, err := FinalizeOrder(test.subroutines)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, names(ordered))
+		})
+	}
+}
+
+func TestReconcileFinalizeOrder(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New().Logger
+
+	newInstance := func() *pmtesting.TestApiObject {
+		return &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{
+			Name: nName.Name, Namespace: nName.Namespace, DeletionTimestamp: &metav1.Time{Time: time.Now()}, Finalizers: []string{"fga", "children", "tenant"},
+		}}
+	}
+
+	t.Run("finalizes in the declared order", func(t *testing.T) {
+		// Arrange
+		instance := newInstance()
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		tracker := &recordingTracker{}
+		injector, err := fault.NewInjector()
+		require.NoError(t, err)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{
+			pmtesting.OrderedSubroutine{Name: "tenant", After: []string{"children"}},
+			injector.Subroutine(pmtesting.OrderedSubroutine{Name: "fga", Priority: 1}),
+			pmtesting.OrderedSubroutine{Name: "children"},
+		}}).WithReconcileTracker(tracker)
+
+		// Act
+		_, err = Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"fga", "children", "tenant"}, tracker.subroutines)
+	})
+
+	t.Run("waits for dependencies requesting a requeue", func(t *testing.T) {
+		// Arrange
+		instance := newInstance()
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		tracker := &recordingTracker{}
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{
+			pmtesting.OrderedSubroutine{Name: "tenant", After: []string{"children"}},
+			pmtesting.OrderedSubroutine{Name: "fga", Priority: 1},
+			pmtesting.OrderedSubroutine{Name: "children", FinalizeRequeueAfter: time.Minute},
+		}}).WithReconcileTracker(tracker)
+
+		// Act
+		result, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, result.RequeueAfter)
+		assert.Equal(t, []string{"fga", "children"}, tracker.subroutines)
+		assert.ElementsMatch(t, []string{"children", "tenant"}, instance.GetFinalizers())
+	})
+
+	t.Run("fails for cycles", func(t *testing.T) {
+		// Arrange
+		instance := newInstance()
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{
+			pmtesting.OrderedSubroutine{Name: "tenant", After: []string{"children"}},
+			pmtesting.OrderedSubroutine{Name: "children", After: []string{"tenant"}},
+		}}
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		assert.EqualError(t, err, "the finalize order of the subroutines children, tenant has a cycle")
+	})
+}
@@ -0,0 +1,104 @@
+package lifecycle
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/logger"
+)
+
+const (
+	EventReasonFinalizerRemoved  = "LegacyFinalizerRemoved"
+	EventReasonFinalizerMigrated = "LegacyFinalizerMigrated"
+
+	eventActionMigrateFinalizers = "MigrateFinalizers"
+)
+
+// MigrateLegacyFinalizers removes legacy finalizers which are not owned by any of the current subroutines
+// or replaces them with their migration target. Since finalizers cannot be added to instances in deletion,
+// legacy finalizers with a migration target are kept in that case and removed together with the target
+// finalizer once the owning subroutine finalized the instance.
+func MigrateLegacyFinalizers(ctx context.Context, cl client.Client, instance runtimeobject.RuntimeObject, l api.Lifecycle, log *logger.Logger) error {
+	m, ok := l.(api.FinalizerMigratingLifecycle)
+	if !ok || l.Config().ReadOnly || len(m.LegacyFinalizers()) == 0 {
+		return nil
+	}
+
+	owned := ownedFinalizers(instance, l.Subroutines())
+	inDeletion := !instance.GetDeletionTimestamp().IsZero()
+	original := instance.DeepCopyObject().(client.Object)
+
+	var changed []api.LegacyFinalizer
+	for _, lf := range m.LegacyFinalizers() {
+		if owned[lf.Name] || !controllerutil.ContainsFinalizer(instance, lf.Name) {
+			continue
+		}
+		if lf.MigrateTo != "" && inDeletion {
+			continue
+		}
+		controllerutil.RemoveFinalizer(instance, lf.Name)
+		if lf.MigrateTo != "" {
+			controllerutil.AddFinalizer(instance, lf.MigrateTo)
+		}
+		changed = append(changed, lf)
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if err := cl.Patch(ctx, instance, client.MergeFrom(original)); err != nil {
+		return err
+	}
+
+	for _, lf := range changed {
+		if lf.MigrateTo != "" {
+			log.Info().Str("finalizer", lf.Name).Str("migratedTo", lf.MigrateTo).Msg("migrated legacy finalizer")
+			recordEvent(l, instance, corev1.EventTypeNormal, EventReasonFinalizerMigrated, eventActionMigrateFinalizers, "Migrated legacy finalizer %s to %s", lf.Name, lf.MigrateTo)
+			continue
+		}
+		log.Info().Str("finalizer", lf.Name).Msg("removed legacy finalizer")
+		recordEvent(l, instance, corev1.EventTypeNormal, EventReasonFinalizerRemoved, eventActionMigrateFinalizers, "Removed legacy finalizer %s", lf.Name)
+	}
+	return nil
+}
+
+// legacyFinalizersOf returns the legacy finalizers migrating to one of the finalizers of the subroutine
+func legacyFinalizersOf(instance runtimeobject.RuntimeObject, s subroutine.Subroutine, l api.Lifecycle) []string {
+	m, ok := l.(api.FinalizerMigratingLifecycle)
+	if !ok {
+		return nil
+	}
+
+	var legacy []string
+	for _, lf := range m.LegacyFinalizers() {
+		if lf.MigrateTo != "" && slices.Contains(s.Finalizers(instance), lf.MigrateTo) {
+			legacy = append(legacy, lf.Name)
+		}
+	}
+	return legacy
+}
+
+func ownedFinalizers(instance runtimeobject.RuntimeObject, subroutines []subroutine.Subroutine) map[string]bool {
+	owned := map[string]bool{}
+	for _, s := range subroutines {
+		for _, f := range s.Finalizers(instance) {
+			owned[f] = true
+		}
+	}
+	return owned
+}
+
+func recordEvent(l api.Lifecycle, instance runtimeobject.RuntimeObject, eventtype, reason, action, note string, args ...any) {
+	r, ok := l.(api.EventRecordingLifecycle)
+	if !ok || r.EventRecorder() == nil {
+		return
+	}
+	r.EventRecorder().Eventf(instance, nil, eventtype, reason, action, note, args...)
+}
@@ -0,0 +1,142 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func TestMigrateLegacyFinalizers(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New()
+
+	t.Run("removes legacy finalizers not owned by any subroutine", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace, Finalizers: []string{"legacy", "foreign"}}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		recorder := events.NewFakeRecorder(10)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.FinalizerSubroutine{}}}).
+			WithLegacyFinalizers(api.LegacyFinalizer{Name: "legacy"}).
+			WithEventRecorder(recorder)
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"foreign", pmtesting.SubroutineFinalizer}, instance.Finalizers)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal LegacyFinalizerRemoved Removed legacy finalizer legacy", <-recorder.Events)
+	})
+
+	t.Run("migrates legacy finalizers to their target", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace, Finalizers: []string{"legacy"}}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		recorder := events.NewFakeRecorder(10)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.FinalizerSubroutine{}}}).
+			WithLegacyFinalizers(api.LegacyFinalizer{Name: "legacy", MigrateTo: pmtesting.SubroutineFinalizer}).
+			WithEventRecorder(recorder)
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{pmtesting.SubroutineFinalizer}, instance.Finalizers)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Normal LegacyFinalizerMigrated Migrated legacy finalizer legacy to finalizer", <-recorder.Events)
+	})
+
+	t.Run("keeps legacy finalizers still owned by a subroutine", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace, Finalizers: []string{pmtesting.SubroutineFinalizer}}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.FinalizerSubroutine{}}}).
+			WithLegacyFinalizers(api.LegacyFinalizer{Name: pmtesting.SubroutineFinalizer})
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{pmtesting.SubroutineFinalizer}, instance.Finalizers)
+	})
+
+	t.Run("removes orphaned legacy finalizers in deletion", func(t *testing.T) {
+		// Arrange
+		now := metav1.NewTime(time.Now())
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace, DeletionTimestamp: &now, Finalizers: []string{"legacy", "foreign"}}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.FinalizerSubroutine{}}}).
+			WithLegacyFinalizers(api.LegacyFinalizer{Name: "legacy"})
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foreign"}, instance.Finalizers)
+	})
+
+	t.Run("finalizes migrated legacy finalizers in deletion", func(t *testing.T) {
+		// Arrange
+		now := metav1.NewTime(time.Now())
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace, DeletionTimestamp: &now, Finalizers: []string{"legacy", "foreign"}}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.FinalizerSubroutine{RequeueAfter: time.Second}}}).
+			WithLegacyFinalizers(api.LegacyFinalizer{Name: "legacy", MigrateTo: pmtesting.SubroutineFinalizer})
+
+		// Act
+		result, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, result.RequeueAfter)
+		assert.ElementsMatch(t, []string{"legacy", "foreign"}, instance.Finalizers)
+
+		// Act
+		mgr.SubroutinesArr = []subroutine.Subroutine{pmtesting.FinalizerSubroutine{}}
+		_, err = Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"foreign"}, instance.Finalizers)
+	})
+
+	t.Run("does nothing in read-only mode", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace, Finalizers: []string{"legacy"}}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := &readOnlyLifecycle{TestLifecycleManager: (&pmtesting.TestLifecycleManager{Logger: log.Logger}).WithLegacyFinalizers(api.LegacyFinalizer{Name: "legacy"})}
+
+		// Act
+		err := MigrateLegacyFinalizers(ctx, fakeClient, instance, mgr, log.Logger)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"legacy"}, instance.Finalizers)
+	})
+}
+
+type readOnlyLifecycle struct {
+	*pmtesting.TestLifecycleManager
+}
+
+func (r *readOnlyLifecycle) Config() api.Config {
+	cfg := r.TestLifecycleManager.Config()
+	cfg.ReadOnly = true
+	return cfg
+}
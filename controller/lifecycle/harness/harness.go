@@ -0,0 +1,289 @@
+// Package harness drives a lifecycle against a client, usually a fake client, until the instance reaches a steady
+// state. Time is controlled by a fake clock, which is advanced by the requested requeue delays and the delays of the
+// rate limiter, and every reconcile is recorded with the called subroutines, the status and the finalizers.
+package harness
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/ratelimiter"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/spread"
+	"github.com/platform-mesh/golang-commons/sentry"
+)
+
+// Step records a single reconcile
+type Step struct {
+	Iteration int
+	// Time is the time of the fake clock when the reconcile started
+	Time        time.Time
+	Result      ctrl.Result
+	Err         error
+	Subroutines []string
+	// Status is the status of the instance after the reconcile, or nil if the instance was deleted
+	Status            map[string]any
+	StatusChanged     bool
+	Finalizers        []string
+	AddedFinalizers   []string
+	RemovedFinalizers []string
+	Deleted           bool
+}
+
+// Steady returns whether the reconcile ended in a steady state. This is the case if the instance was deleted, or if
+// the reconcile succeeded without changing status and finalizers and did not request a requeue. A requeue is
+// accepted if no subroutine was called, e.g. for the periodic requeue of spread reconciles.
+func (s Step) Steady() bool {
+	if s.Deleted {
+		return true
+	}
+	if s.Err != nil || s.StatusChanged || len(s.AddedFinalizers) > 0 || len(s.RemovedFinalizers) > 0 {
+		return false
+	}
+	return s.Result.RequeueAfter == 0 || len(s.Subroutines) == 0
+}
+
+type Config struct {
+	// MaxIterations limits the reconciles of RunUntilSteady
+	MaxIterations int
+	// Start is the initial time of the fake clock
+	Start time.Time
+	// RateLimiter calculates the delay after failed reconciles, defaults to the static then exponential rate limiter
+	// of the lifecycle using the fake clock
+	RateLimiter workqueue.TypedRateLimiter[reconcile.Request]
+}
+
+type Option func(*Config)
+
+func WithMaxIterations(n int) Option {
+	return func(c *Config) {
+		c.MaxIterations = n
+	}
+}
+
+func WithStart(start time.Time) Option {
+	return func(c *Config) {
+		c.Start = start
+	}
+}
+
+func WithRateLimiter(rateLimiter workqueue.TypedRateLimiter[reconcile.Request]) Option {
+	return func(c *Config) {
+		c.RateLimiter = rateLimiter
+	}
+}
+
+type Harness struct {
+	client      client.Client
+	lifecycle   api.Lifecycle
+	instance    reflect.Type
+	request     reconcile.Request
+	clock       *clocktesting.FakeClock
+	restore     func()
+	rateLimiter workqueue.TypedRateLimiter[reconcile.Request]
+	maxIter     int
+	steps       []Step
+}
+
+// New creates a harness reconciling the instance, which has to exist in the client. The spreader of the lifecycle is
+// switched to the fake clock of the harness until Close is called.
+func New(cl client.Client, l api.Lifecycle, instance runtimeobject.RuntimeObject, opts ...Option) (*Harness, error) {
+	cfg := Config{MaxIterations: 20, Start: time.Now()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.MaxIterations <= 0 {
+		return nil, fmt.Errorf("max iterations must be positive")
+	}
+
+	fakeClock := clocktesting.NewFakeClock(cfg.Start)
+	if cfg.RateLimiter == nil {
+		rateLimiter, err := ratelimiter.NewStaticThenExponentialRateLimiter[reconcile.Request](ratelimiter.NewConfig(ratelimiter.WithClock(fakeClock)))
+		if err != nil {
+			return nil, err
+		}
+		cfg.RateLimiter = rateLimiter
+	}
+	restore := func() {}
+	if s, ok := l.Spreader().(*spread.Spreader); ok {
+		previous := s.Clock()
+		s.WithClock(fakeClock)
+		restore = func() { s.WithClock(previous) }
+	}
+
+	return &Harness{
+		client:      cl,
+		lifecycle:   l,
+		instance:    reflect.TypeOf(instance).Elem(),
+		request:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: instance.GetNamespace(), Name: instance.GetName()}},
+		clock:       fakeClock,
+		restore:     restore,
+		rateLimiter: cfg.RateLimiter,
+		maxIter:     cfg.MaxIterations,
+	}, nil
+}
+
+// Close switches the spreader of the lifecycle back to its previous clock
+func (h *Harness) Close() {
+	h.restore()
+}
+
+// Clock returns the fake clock of the harness
+func (h *Harness) Clock() *clocktesting.FakeClock {
+	return h.clock
+}
+
+// Advance advances the fake clock
+func (h *Harness) Advance(d time.Duration) {
+	h.clock.Step(d)
+}
+
+// Steps returns all recorded reconciles
+func (h *Harness) Steps() []Step {
+	return h.steps
+}
+
+// SubroutineCalls returns the names of all called subroutines in order
+func (h *Harness) SubroutineCalls() []string {
+	var calls []string
+	for _, step := range h.steps {
+		calls = append(calls, step.Subroutines...)
+	}
+	return calls
+}
+
+// Get returns the current instance from the client
+func (h *Harness) Get(ctx context.Context) (runtimeobject.RuntimeObject, error) {
+	instance := h.newInstance()
+	if err := h.client.Get(ctx, h.request.NamespacedName, instance); err != nil {
+		return nil, err
+	}
+	return instance, nil
+}
+
+// RunUntilSteady reconciles until a reconcile ends in a steady state and returns the recorded reconciles of this run.
+// Between reconciles, the fake clock is advanced by the requested requeue delay or the delay of the rate limiter.
+func (h *Harness) RunUntilSteady(ctx context.Context) ([]Step, error) {
+	first := len(h.steps)
+	for range h.maxIter {
+		step, err := h.Reconcile(ctx)
+		if err != nil {
+			return h.steps[first:], err
+		}
+		if step.Steady() {
+			return h.steps[first:], nil
+		}
+		switch {
+		case step.Err != nil:
+			h.clock.Step(h.rateLimiter.When(h.request))
+		case step.Result.RequeueAfter > 0:
+			h.clock.Step(step.Result.RequeueAfter)
+		}
+	}
+	return h.steps[first:], fmt.Errorf("no steady state after %d reconciles", h.maxIter)
+}
+
+// Reconcile runs a single reconcile and records it. The returned error is only set if the instance could not be read,
+// errors of the reconcile are recorded in the step.
+func (h *Harness) Reconcile(ctx context.Context) (Step, error) {
+	before, err := h.Get(ctx)
+	if err != nil {
+		return Step{}, fmt.Errorf("failed to get instance before reconcile: %w", err)
+	}
+	beforeStatus, err := statusOf(before)
+	if err != nil {
+		return Step{}, err
+	}
+
+	step := Step{Iteration: len(h.steps) + 1, Time: h.clock.Now()}
+	tracker := &recordingTracker{}
+	step.Result, step.Err = lifecycle.Reconcile(lifecycle.SetReconcileTrackerInContext(ctx, tracker), h.request.NamespacedName, h.newInstance(), h.client, h.lifecycle)
+	step.Subroutines = tracker.subroutines()
+	if step.Err == nil {
+		h.rateLimiter.Forget(h.request)
+	}
+
+	after, err := h.Get(ctx)
+	switch {
+	case kerrors.IsNotFound(err):
+		step.Deleted = true
+		step.RemovedFinalizers = before.GetFinalizers()
+	case err != nil:
+		return Step{}, fmt.Errorf("failed to get instance after reconcile: %w", err)
+	default:
+		if step.Status, err = statusOf(after); err != nil {
+			return Step{}, err
+		}
+		step.StatusChanged = !equality.Semantic.DeepEqual(beforeStatus, step.Status)
+		step.Finalizers = after.GetFinalizers()
+		step.AddedFinalizers = difference(after.GetFinalizers(), before.GetFinalizers())
+		step.RemovedFinalizers = difference(before.GetFinalizers(), after.GetFinalizers())
+	}
+
+	h.steps = append(h.steps, step)
+	return step, nil
+}
+
+func (h *Harness) newInstance() runtimeobject.RuntimeObject {
+	return reflect.New(h.instance).Interface().(runtimeobject.RuntimeObject)
+}
+
+func statusOf(instance runtimeobject.RuntimeObject) (map[string]any, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert instance to unstructured: %w", err)
+	}
+	status, _ := content["status"].(map[string]any)
+	return status, nil
+}
+
+// difference returns the entries of a missing in b
+func difference(a, b []string) []string {
+	var diff []string
+	for _, entry := range a {
+		if !slices.Contains(b, entry) {
+			diff = append(diff, entry)
+		}
+	}
+	return diff
+}
+
+// recordingTracker records the subroutines called by the lifecycle
+type recordingTracker struct {
+	mu     sync.Mutex
+	called []string
+}
+
+func (r *recordingTracker) Track(string, sentry.Tags) api.TrackedReconcile {
+	return r
+}
+
+func (r *recordingTracker) SetSubroutine(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.called = append(r.called, name)
+}
+
+func (r *recordingTracker) Done() {}
+
+func (r *recordingTracker) subroutines() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.called)
+}
@@ -0,0 +1,176 @@
+package harness
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/controllerruntime"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/spread"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestRunUntilSteady(t *testing.T) {
+	ctx := context.Background()
+	log := testlogger.New().Logger
+
+	t.Run("records finalizers, subroutine calls and status until steady", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{pmtesting.FinalizerSubroutine{Client: fakeClient}}}
+		h, err := New(fakeClient, mgr, instance, WithStart(start))
+		require.NoError(t, err)
+
+		// Act
+		steps, err := h.RunUntilSteady(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, steps, 2)
+		assert.Equal(t, []string{pmtesting.SubroutineFinalizer}, steps[0].AddedFinalizers)
+		assert.True(t, steps[0].StatusChanged)
+		assert.Equal(t, "other string", steps[0].Status["Some"])
+		assert.True(t, steps[1].Steady())
+		assert.Equal(t, []string{"changeStatus", "changeStatus"}, h.SubroutineCalls())
+	})
+
+	t.Run("advances the clock by the requested delay", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{pmtesting.FailureScenarioSubroutine{RequeAfter: true}}}
+		h, err := New(fakeClient, mgr, instance, WithStart(start), WithMaxIterations(3))
+		require.NoError(t, err)
+
+		// Act
+		steps, err := h.RunUntilSteady(ctx)
+
+		// Assert
+		assert.EqualError(t, err, "no steady state after 3 reconciles")
+		require.Len(t, steps, 3)
+		assert.Equal(t, start.Add(20*time.Second), steps[2].Time)
+		assert.Equal(t, start.Add(30*time.Second), h.Clock().Now())
+	})
+
+	t.Run("advances the clock by the rate limiter after errors", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{pmtesting.FailureScenarioSubroutine{Retry: true}}}
+		h, err := New(fakeClient, mgr, instance, WithStart(start), WithMaxIterations(2))
+		require.NoError(t, err)
+
+		// Act
+		steps, err := h.RunUntilSteady(ctx)
+
+		// Assert
+		assert.Error(t, err)
+		require.Len(t, steps, 2)
+		assert.EqualError(t, steps[0].Err, "FailureScenarioSubroutine")
+		assert.Equal(t, start.Add(2*time.Second), steps[1].Time)
+	})
+
+	t.Run("reaches a steady state with spread reconciles", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.CommonStatusApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", Generation: 1}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := controllerruntime.NewLifecycleManager([]subroutine.Subroutine{pmtesting.ContextValueSubroutine{}}, "op", "ctrl", fakeClient, log).
+			WithSpreadingReconciles()
+		h, err := New(fakeClient, mgr, instance, WithStart(start))
+		require.NoError(t, err)
+		defer h.Close()
+
+		// Act
+		steps, err := h.RunUntilSteady(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, steps, 2)
+		assert.True(t, steps[0].StatusChanged)
+		assert.Empty(t, steps[1].Subroutines)
+		assert.Greater(t, steps[1].Result.RequeueAfter, 12*time.Hour)
+
+		// Act
+		h.Advance(24 * time.Hour)
+		steps, err = h.RunUntilSteady(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ContextValueSubroutine"}, steps[0].Subroutines)
+	})
+
+	t.Run("records the deletion", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{
+			Name: "foo", Namespace: "bar", DeletionTimestamp: &metav1.Time{Time: start}, Finalizers: []string{pmtesting.SubroutineFinalizer},
+		}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{pmtesting.FinalizerSubroutine{Client: fakeClient}}}
+		h, err := New(fakeClient, mgr, instance, WithStart(start))
+		require.NoError(t, err)
+
+		// Act
+		steps, err := h.RunUntilSteady(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, steps, 1)
+		assert.True(t, steps[0].Deleted)
+		assert.Equal(t, []string{pmtesting.SubroutineFinalizer}, steps[0].RemovedFinalizers)
+	})
+}
+
+func TestSnapshotSteadyState(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	log := testlogger.New().HideLogOutput()
+	instance := &pmtesting.CommonStatusApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", Generation: 1}}
+	cl := pmtesting.NewRecordingClient(pmtesting.CreateFakeClient(t, instance))
+	mgr := controllerruntime.NewLifecycleManager([]subroutine.Subroutine{pmtesting.ContextValueSubroutine{}}, "op", "ctrl", cl, log.Logger).
+		WithConditionManagement()
+	h, err := New(cl, mgr, instance, WithStart(start))
+	require.NoError(t, err)
+
+	// Act
+	_, err = h.RunUntilSteady(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	pmtesting.MatchSnapshot(t, "steady_state", cl, instance,
+		pmtesting.WithWrittenObjects(cl),
+		pmtesting.WithLogs(log, pmtesting.LogMessageContains("start reconcile", "end reconcile")))
+}
+
+func TestNew(t *testing.T) {
+	instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+	_, err := New(pmtesting.CreateFakeClient(t, instance), &pmtesting.TestLifecycleManager{}, instance, WithMaxIterations(0))
+	assert.EqualError(t, err, "max iterations must be positive")
+}
+
+func TestClose(t *testing.T) {
+	// Arrange
+	instance := &pmtesting.CommonStatusApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+	fakeClient := pmtesting.CreateFakeClient(t, instance)
+	mgr := controllerruntime.NewLifecycleManager(nil, "op", "ctrl", fakeClient, testlogger.New().Logger).
+		WithSpreadingReconciles()
+	spreader := mgr.Spreader().(*spread.Spreader)
+	previous := spreader.Clock()
+	h, err := New(fakeClient, mgr, instance, WithStart(start))
+	require.NoError(t, err)
+	require.Equal(t, h.Clock(), spreader.Clock())
+
+	// Act
+	h.Close()
+
+	// Assert
+	assert.Equal(t, previous, spreader.Clock())
+}
@@ -0,0 +1,58 @@
+package lifecycle
+
+import (
+	"context"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/spread"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/util"
+	"github.com/platform-mesh/golang-commons/logger"
+)
+
+// subroutineInputUnchanged computes the input hash of the subroutine and reports whether it matches the hash and
+// generation of its last successful run. It returns an empty hash if skipping is not enabled or not applicable.
+func subroutineInputUnchanged(ctx context.Context, instance runtimeobject.RuntimeObject, s subroutine.Subroutine, l api.Lifecycle, inDeletion bool, log *logger.Logger) (string, bool) {
+	h, ok := l.(api.InputHashingLifecycle)
+	if !ok || !h.SkipUnchangedSubroutines() || inDeletion {
+		return "", false
+	}
+	hasher, ok := s.(subroutine.InputHasher)
+	if !ok {
+		return "", false
+	}
+
+	hash, err := hasher.InputHash(ctx, instance)
+	if err != nil {
+		// Without a hash the subroutine is always processed
+		log.Warn().Err(err.Err()).Str("subroutine", s.GetName()).Msg("failed to compute subroutine input hash")
+		return "", false
+	}
+
+	if _, refresh := instance.GetLabels()[spread.ReconcileRefreshLabel]; refresh {
+		return hash, false
+	}
+
+	for _, last := range util.MustToInterface[api.RuntimeObjectSubroutineInputHashes](instance, log).GetSubroutineInputHashes() {
+		if last.Name == s.GetName() {
+			return hash, last.Hash == hash && last.ObservedGeneration == instance.GetGeneration()
+		}
+	}
+	return hash, false
+}
+
+// storeSubroutineInputHash persists the input hash of a successful subroutine run in the status of the instance
+func storeSubroutineInputHash(instance runtimeobject.RuntimeObject, s subroutine.Subroutine, hash string, log *logger.Logger) {
+	hashesObj := util.MustToInterface[api.RuntimeObjectSubroutineInputHashes](instance, log)
+	hashes := hashesObj.GetSubroutineInputHashes()
+	current := api.SubroutineInputHash{Name: s.GetName(), Hash: hash, ObservedGeneration: instance.GetGeneration()}
+	for i := range hashes {
+		if hashes[i].Name == s.GetName() {
+			hashes[i] = current
+			hashesObj.SetSubroutineInputHashes(hashes)
+			return
+		}
+	}
+	hashesObj.SetSubroutineInputHashes(append(hashes, current))
+}
@@ -0,0 +1,145 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/spread"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	operrors "github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func TestSkipUnchangedSubroutines(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New()
+
+	newInstance := func() *pmtesting.ImplementSubroutineInputHashes {
+		return &pmtesting.ImplementSubroutineInputHashes{TestApiObject: pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace, Generation: 1}}}
+	}
+
+	t.Run("stores the input hash and skips unchanged inputs", func(t *testing.T) {
+		// Arrange
+		processed := 0
+		instance := newInstance()
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.HashingSubroutine{Processed: &processed}}}).
+			WithSkipUnchangedSubroutines()
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+		require.NoError(t, err)
+		_, err = Reconcile(ctx, nName, instance, fakeClient, mgr)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, 1, processed)
+		persisted := newInstance()
+		require.NoError(t, fakeClient.Get(ctx, nName, persisted))
+		assert.Equal(t, []api.SubroutineInputHash{{Name: "HashingSubroutine", Hash: "hash-", ObservedGeneration: 1}}, persisted.Status.InputHashes)
+	})
+
+	t.Run("processes changed inputs", func(t *testing.T) {
+		// Arrange
+		processed := 0
+		instance := newInstance()
+		instance.Status.InputHashes = []api.SubroutineInputHash{{Name: "HashingSubroutine", Hash: "hash-old", ObservedGeneration: 1}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.HashingSubroutine{Processed: &processed}}}).
+			WithSkipUnchangedSubroutines()
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		assert.Equal(t, "hash-", instance.Status.InputHashes[0].Hash)
+	})
+
+	t.Run("processes a changed generation", func(t *testing.T) {
+		// Arrange
+		processed := 0
+		instance := newInstance()
+		instance.Generation = 2
+		instance.Status.InputHashes = []api.SubroutineInputHash{{Name: "HashingSubroutine", Hash: "hash-", ObservedGeneration: 1}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.HashingSubroutine{Processed: &processed}}}).
+			WithSkipUnchangedSubroutines()
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+	})
+
+	t.Run("processes unchanged inputs with the refresh label", func(t *testing.T) {
+		// Arrange
+		processed := 0
+		instance := newInstance()
+		instance.Labels = map[string]string{spread.ReconcileRefreshLabel: "true"}
+		instance.Status.InputHashes = []api.SubroutineInputHash{{Name: "HashingSubroutine", Hash: "hash-", ObservedGeneration: 1}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.HashingSubroutine{Processed: &processed}}}).
+			WithSkipUnchangedSubroutines()
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+	})
+
+	t.Run("processes the subroutine if the hash cannot be computed", func(t *testing.T) {
+		// Arrange
+		processed := 0
+		instance := newInstance()
+		instance.Status.InputHashes = []api.SubroutineInputHash{{Name: "HashingSubroutine", Hash: "hash-", ObservedGeneration: 1}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		hashErr := operrors.NewOperatorError(errors.New("referenced object not found"), true, false)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.HashingSubroutine{Processed: &processed, HashErr: hashErr}}}).
+			WithSkipUnchangedSubroutines()
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+	})
+
+	t.Run("ignores hashes if not enabled", func(t *testing.T) {
+		// Arrange
+		processed := 0
+		instance := newInstance()
+		instance.Status.InputHashes = []api.SubroutineInputHash{{Name: "HashingSubroutine", Hash: "hash-", ObservedGeneration: 1}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.HashingSubroutine{Processed: &processed}}}
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+	})
+
+	t.Run("requires the input hash status interface", func(t *testing.T) {
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger}).WithSkipUnchangedSubroutines()
+
+		assert.Error(t, ValidateInterfaces(&pmtesting.TestApiObject{}, log.Logger, mgr))
+		assert.NoError(t, ValidateInterfaces(newInstance(), log.Logger, mgr))
+	})
+}
@@ -0,0 +1,75 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pmclient "github.com/platform-mesh/golang-commons/controller/client"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+type clientCapturingSubroutine struct {
+	captured *client.Client
+}
+
+func (s clientCapturingSubroutine) Process(ctx context.Context, _ runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	*s.captured = pmclient.LoadClientFromContext(ctx, nil)
+	return ctrl.Result{}, nil
+}
+
+func (s clientCapturingSubroutine) Finalize(context.Context, runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	return ctrl.Result{}, nil
+}
+
+func (s clientCapturingSubroutine) GetName() string { return "clientCapturingSubroutine" }
+
+func (s clientCapturingSubroutine) Finalizers(runtimeobject.RuntimeObject) []string { return nil }
+
+func TestClientInstrumentation(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New()
+
+	t.Run("passes an instrumented client to subroutines", func(t *testing.T) {
+		// Arrange
+		var captured client.Client
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		instrumentation, err := pmclient.NewInstrumentation()
+		require.NoError(t, err)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{clientCapturingSubroutine{captured: &captured}}}).
+			WithClientInstrumentation(instrumentation)
+
+		// Act
+		_, err = Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotNil(t, captured)
+		assert.NotEqual(t, fakeClient, captured)
+		assert.NoError(t, captured.Get(ctx, nName, &pmtesting.TestApiObject{}))
+	})
+
+	t.Run("passes no client without instrumentation", func(t *testing.T) {
+		var captured client.Client
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{clientCapturingSubroutine{captured: &captured}}}
+
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		require.NoError(t, err)
+		assert.Nil(t, captured)
+	})
+}
@@ -3,11 +3,16 @@ package lifecycle
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"runtime/debug"
 	"slices"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -18,10 +23,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	mccontext "sigs.k8s.io/multicluster-runtime/pkg/context"
 
+	"github.com/platform-mesh/golang-commons/context/keys"
+	pmclient "github.com/platform-mesh/golang-commons/controller/client"
+	pmerrors "github.com/platform-mesh/golang-commons/controller/errors"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/checkpoint"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/conditions"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/protection"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/util"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/watch"
 	"github.com/platform-mesh/golang-commons/errors"
 	"github.com/platform-mesh/golang-commons/logger"
 	"github.com/platform-mesh/golang-commons/sentry"
@@ -40,9 +52,17 @@ func Reconcile(ctx context.Context, nName types.NamespacedName, instance runtime
 	}
 	sentryTags := sentry.Tags{"namespace": nName.Namespace, "name": nName.Name}
 
+	tracked := trackReconcile(ctx, l, nName, cluster, sentryTags)
+	defer tracked.Done()
+
 	ctx = logger.SetLoggerInContext(ctx, log)
 	ctx = sentry.ContextWithSentryTags(ctx, sentryTags)
 
+	if l.Config().ReadOnly {
+		// The lifecycle does not write in read-only mode, the client guards against writes slipping through
+		cl = readOnlyClientOf(cl, "lifecycle", l)
+	}
+
 	log.Info().Msg("start reconcile")
 
 	err := cl.Get(ctx, nName, instance)
@@ -62,11 +82,22 @@ func Reconcile(ctx context.Context, nName types.NamespacedName, instance runtime
 		reconcileRequired := l.Spreader().ReconcileRequired(instance, log)
 		if !reconcileRequired {
 			log.Info().Msg("skipping reconciliation, spread reconcile is active. No processing needed")
-			return l.Spreader().OnNextReconcile(instance, log)
+			result, err := l.Spreader().OnNextReconcile(instance, log)
+			if p, ok := l.(api.PrioritizingLifecycle); ok && p.QueuePolicy() != nil {
+				result.Priority = p.QueuePolicy().ResyncPriority(l.Config().ControllerName)
+			}
+			return result, err
 		}
 	}
 
+	if inDeletion && protection.IsDeletionProtected(instance) {
+		return blockDeletion(ctx, cl, originalCopy, instance, l, log, generationChanged, sentryTags)
+	}
+
 	// Manage Finalizers
+	if merr := MigrateLegacyFinalizers(ctx, cl, instance, l, log); merr != nil {
+		return HandleClientError("failed to migrate legacy finalizers", log, merr, generationChanged, sentryTags)
+	}
 	ferr := AddFinalizersIfNeeded(ctx, cl, instance, l.Subroutines(), l.Config().ReadOnly)
 	if ferr != nil {
 		return ctrl.Result{}, ferr
@@ -76,6 +107,7 @@ func Reconcile(ctx context.Context, nName types.NamespacedName, instance runtime
 	if l.ConditionsManager() != nil {
 		condArr = util.MustToInterface[api.RuntimeObjectConditions](instance, log).GetConditions()
 		l.ConditionsManager().SetInstanceConditionUnknownIfNotSet(&condArr, instance.GetGeneration())
+		meta.RemoveStatusCondition(&condArr, conditions.ConditionDeletionBlocked)
 	}
 
 	if l.PrepareContextFunc() != nil {
@@ -86,15 +118,27 @@ func Reconcile(ctx context.Context, nName types.NamespacedName, instance runtime
 		ctx = localCtx
 	}
 
-	// In case of deletion execute the finalize subroutines in the reverse order as subroutine processing
+	// In case of deletion execute the finalize subroutines in the finalize order, by default the reverse order as
+	// subroutine processing
 	subroutines := make([]subroutine.Subroutine, len(l.Subroutines()))
 	copy(subroutines, l.Subroutines())
 	if inDeletion {
-		slices.Reverse(subroutines)
+		ordered, oErr := FinalizeOrder(subroutines)
+		if oErr != nil {
+			log.Error().Err(oErr).Msg("invalid finalize order")
+			return ctrl.Result{}, oErr
+		}
+		subroutines = ordered
 	}
 
 	// Continue with reconciliation
 	for _, s := range subroutines {
+		if inDeletion {
+			if dependency, pending := pendingFinalizeDependency(instance, s, subroutines); pending {
+				log.Debug().Str("subroutine", s.GetName()).Str("dependency", dependency).Msg("skipping finalization, dependency is not finalized yet")
+				continue
+			}
+		}
 		if l.ConditionsManager() != nil {
 			l.ConditionsManager().SetSubroutineConditionToUnknownIfNotSet(&condArr, instance.GetGeneration(), s, inDeletion, log)
 		}
@@ -103,7 +147,21 @@ func Reconcile(ctx context.Context, nName types.NamespacedName, instance runtime
 		if l.ConditionsManager() != nil {
 			util.MustToInterface[api.RuntimeObjectConditions](instance, log).SetConditions(condArr)
 		}
-		subResult, retry, err := reconcileSubroutine(ctx, instance, s, cl, l, log, generationChanged, sentryTags)
+		var subResult ctrl.Result
+		var retry bool
+		var err error
+		inputHash, unchanged := subroutineInputUnchanged(ctx, instance, s, l, inDeletion, log)
+		if unchanged {
+			log.Debug().Str("subroutine", s.GetName()).Msg("skipping subroutine, input is unchanged")
+		} else {
+			tracked.SetSubroutine(s.GetName())
+			start := time.Now()
+			subResult, retry, err = reconcileSubroutine(ctx, instance, s, cl, l, log, generationChanged, sentryTags)
+			recordSubroutineRun(instance, s, start, subResult, err, l, log)
+			if err == nil && subResult.RequeueAfter == 0 && inputHash != "" {
+				storeSubroutineInputHash(instance, s, inputHash, log)
+			}
+		}
 		// Update condArr with any changes the s did
 		if l.ConditionsManager() != nil {
 			condArr = util.MustToInterface[api.RuntimeObjectConditions](instance, log).GetConditions()
@@ -123,6 +181,11 @@ func Reconcile(ctx context.Context, nName types.NamespacedName, instance runtime
 			if !retry {
 				return ctrl.Result{}, nil
 			}
+			// Delays are only requested for expected waits, e.g. for a dependency, which are not reconcile errors
+			if delay, ok := pmerrors.RequeueAfter(err); ok {
+				log.Debug().Dur("requeueAfter", delay).Msg("requeueing after the delay requested by the error")
+				return ctrl.Result{RequeueAfter: delay}, nil
+			}
 			return subResult, err
 		}
 		if subResult.RequeueAfter > 0 {
@@ -146,14 +209,14 @@ func Reconcile(ctx context.Context, nName types.NamespacedName, instance runtime
 		}
 	}
 
-	if t, ok := l.(api.TerminatingLifecycle); ok && result.RequeueAfter == 0 && inDeletion && t.Terminator() != "" {
+	if t, ok := l.(api.TerminatingLifecycle); ok && result.RequeueAfter == 0 && inDeletion && t.Terminator() != "" && !l.Config().ReadOnly {
 		log.Debug().Msgf("Removing terminator")
 		if err := removeTerminator(ctx, instance, cl, t.Terminator()); err != nil {
 			return result, fmt.Errorf("potentially removing Terminator: %w", err)
 		}
 	}
 
-	if i, ok := l.(api.InitializingLifecycle); ok && result.RequeueAfter == 0 && !inDeletion && i.Initializer() != "" {
+	if i, ok := l.(api.InitializingLifecycle); ok && result.RequeueAfter == 0 && !inDeletion && i.Initializer() != "" && !l.Config().ReadOnly {
 		log.Debug().Msgf("Removing initializer")
 		if err := removeInitializer(ctx, instance, cl, i.Initializer()); err != nil {
 			return result, fmt.Errorf("potentially removing Initializer: %w", err)
@@ -198,8 +261,27 @@ func reconcileSubroutine(ctx context.Context, instance runtimeobject.RuntimeObje
 
 	ctx, span := otel.Tracer(l.Config().OperatorName).Start(ctx, fmt.Sprintf("%s.reconcileSubroutine.%s", l.Config().ControllerName, s.GetName()))
 	defer span.End()
-	var result ctrl.Result
-	var err errors.OperatorError
+	if subroutineClient := subroutineClientOf(cl, s, l); subroutineClient != nil {
+		ctx = pmclient.SetClientInContext(ctx, subroutineClient)
+	}
+	result, err := callSubroutine(ctx, instance, s, cl, l, subroutineLogger, sentryTags)
+	if err != nil {
+		if generationChanged && err.Sentry() {
+			sentry.CaptureError(err.Err(), sentryTags)
+		}
+		subroutineLogger.Error().Err(err.Err()).Bool("retry", err.Retry()).Msg("subroutine ended with error")
+		return result, err.Retry(), err.Err()
+	}
+
+	subroutineLogger.Debug().Msg("end subroutine")
+	return result, false, nil
+}
+
+// callSubroutine terminates, finalizes, initializes or processes the instance depending on its state. A panic of the
+// subroutine is recovered and returned as retriable error.
+func callSubroutine(ctx context.Context, instance runtimeobject.RuntimeObject, s subroutine.Subroutine, cl client.Client, l api.Lifecycle, subroutineLogger *logger.Logger, sentryTags map[string]string) (result ctrl.Result, err errors.OperatorError) {
+	defer recoverSubroutinePanic(s, &result, &err, subroutineLogger, sentryTags)
+
 	if terminator, ok := s.(subroutine.Terminator); ok && instance.GetDeletionTimestamp() != nil {
 		subroutineLogger.Debug().Msg("terminating instance")
 		result, err = terminator.Terminate(ctx, instance)
@@ -210,13 +292,18 @@ func reconcileSubroutine(ctx context.Context, instance runtimeobject.RuntimeObje
 			}
 			subroutineLogger.Error().Err(err.Err()).Bool("retry", err.Retry()).Msg("terminator ended with error")
 		}
-	} else if instance.GetDeletionTimestamp() != nil && containsFinalizer(instance, s.Finalizers(instance)) {
+	} else if instance.GetDeletionTimestamp() != nil && containsFinalizer(instance, slices.Concat(s.Finalizers(instance), legacyFinalizersOf(instance, s, l))) {
 		subroutineLogger.Debug().Msg("finalizing instance")
-		result, err = s.Finalize(ctx, instance)
+		progress := checkpoint.New(instance, s.GetName())
+		result, err = s.Finalize(checkpoint.SetCheckpointInContext(ctx, progress), instance)
 		subroutineLogger.Debug().Any("result", result).Msg("finalized instance")
 		if err == nil {
 			// Remove finalizers unless requeue is requested
-			err = removeFinalizerIfNeeded(ctx, instance, s, result, l.Config().ReadOnly, cl)
+			err = removeFinalizerIfNeeded(ctx, instance, s, result, l.Config().ReadOnly, cl, legacyFinalizersOf(instance, s, l)...)
+		}
+		if err == nil && result.RequeueAfter == 0 && !l.Config().ReadOnly {
+			// The finalizer is removed, so the progress of the finalization is no longer needed
+			progress.Clear()
 		}
 	} else if initializer, ok := s.(subroutine.Initializer); ok && instance.GetDeletionTimestamp() == nil {
 		subroutineLogger.Debug().Msg("initializing instance")
@@ -233,17 +320,108 @@ func reconcileSubroutine(ctx context.Context, instance runtimeobject.RuntimeObje
 		result, err = s.Process(ctx, instance)
 		subroutineLogger.Debug().Any("result", result).Msg("processed instance")
 	}
+	return result, err
+}
 
-	if err != nil {
-		if generationChanged && err.Sentry() {
-			sentry.CaptureError(err.Err(), sentryTags)
+// recoverSubroutinePanic recovers a panic of the subroutine, reports it to Sentry with the stack and replaces the
+// result with a retriable error. The error is not reported to Sentry again.
+func recoverSubroutinePanic(s subroutine.Subroutine, result *ctrl.Result, err *errors.OperatorError, log *logger.Logger, sentryTags map[string]string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	panicErr := &pmerrors.PanicError{Subroutine: s.GetName(), Value: r, Stack: debug.Stack()}
+	log.Error().Err(panicErr).Str("stack", string(panicErr.Stack)).Msg("recovered subroutine panic")
+	sentry.CaptureError(panicErr, sentryTags, sentry.Extras{"stack": string(panicErr.Stack)})
+	*result = ctrl.Result{}
+	*err = errors.NewOperatorError(panicErr, true, false)
+}
+
+// subroutineClientOf returns the client passed to the subroutine via the context, instrumented if the lifecycle
+// instruments clients and guarded against writes in read-only mode, or nil if neither applies
+func subroutineClientOf(cl client.Client, s subroutine.Subroutine, l api.Lifecycle) client.Client {
+	var subroutineClient client.Client
+	if i, ok := l.(api.ClientInstrumentingLifecycle); ok && i.ClientInstrumentation() != nil {
+		subroutineClient = i.ClientInstrumentation().Wrap(cl, s.GetName())
+	}
+	if l.Config().ReadOnly {
+		if subroutineClient == nil {
+			subroutineClient = cl
 		}
-		subroutineLogger.Error().Err(err.Err()).Bool("retry", err.Retry()).Msg("subroutine ended with error")
-		return result, err.Retry(), err.Err()
+		subroutineClient = readOnlyClientOf(subroutineClient, s.GetName(), l)
 	}
+	return subroutineClient
+}
 
-	subroutineLogger.Debug().Msg("end subroutine")
-	return result, false, nil
+// readOnlyClientOf returns the client guarded against writes according to the read-only mode of the lifecycle
+func readOnlyClientOf(cl client.Client, name string, l api.Lifecycle) client.Client {
+	mode := pmclient.RejectWrites
+	if l.Config().ReadOnlyDryRun {
+		mode = pmclient.DryRunWrites
+	}
+	return pmclient.NewReadOnlyClient(cl, name, mode)
+}
+
+// SetReconcileTrackerInContext stores a reconcile tracker in the context, which is used in addition to the tracker of
+// the lifecycle, e.g. to observe the subroutine calls of any lifecycle in tests
+func SetReconcileTrackerInContext(ctx context.Context, tracker api.ReconcileTracker) context.Context {
+	return context.WithValue(ctx, keys.TrackerCtxKey, tracker)
+}
+
+// trackReconcile registers the reconcile with the reconcile tracker of the lifecycle and the tracker of the context,
+// if configured
+func trackReconcile(ctx context.Context, l api.Lifecycle, nName types.NamespacedName, cluster string, sentryTags sentry.Tags) api.TrackedReconcile {
+	object := nName.String()
+	if cluster != "" {
+		object = cluster + "/" + object
+	}
+	var tracked multiTrackedReconcile
+	if t, ok := l.(api.TrackingLifecycle); ok && t.ReconcileTracker() != nil {
+		tracked = append(tracked, t.ReconcileTracker().Track(object, sentryTags))
+	}
+	if t, ok := ctx.Value(keys.TrackerCtxKey).(api.ReconcileTracker); ok {
+		tracked = append(tracked, t.Track(object, sentryTags))
+	}
+	switch len(tracked) {
+	case 0:
+		return noopTrackedReconcile{}
+	case 1:
+		return tracked[0]
+	}
+	return tracked
+}
+
+type multiTrackedReconcile []api.TrackedReconcile
+
+func (m multiTrackedReconcile) SetSubroutine(name string) {
+	for _, t := range m {
+		t.SetSubroutine(name)
+	}
+}
+
+func (m multiTrackedReconcile) Done() {
+	for _, t := range m {
+		t.Done()
+	}
+}
+
+type noopTrackedReconcile struct{}
+
+func (noopTrackedReconcile) SetSubroutine(string) {}
+func (noopTrackedReconcile) Done()                {}
+
+// recordSubroutineRun updates the subroutine report of the instance if the lifecycle has a report manager
+func recordSubroutineRun(instance runtimeobject.RuntimeObject, s subroutine.Subroutine, start time.Time, result ctrl.Result, err error, l api.Lifecycle, log *logger.Logger) {
+	r, ok := l.(api.ReportingLifecycle)
+	if !ok || r.ReportManager() == nil {
+		return
+	}
+	reportsObj := util.MustToInterface[api.RuntimeObjectSubroutineReports](instance, log)
+	reports := reportsObj.GetSubroutineReports()
+	object := fmt.Sprintf("%s/%s", client.ObjectKeyFromObject(instance), instance.GetUID())
+	if r.ReportManager().RecordSubroutineRun(&reports, object, instance.GetGeneration(), s, start, result, err) {
+		reportsObj.SetSubroutineReports(reports)
+	}
 }
 
 func containsFinalizer(o client.Object, subroutineFinalizers []string) bool {
@@ -255,7 +433,8 @@ func containsFinalizer(o client.Object, subroutineFinalizers []string) bool {
 	return false
 }
 
-func removeFinalizerIfNeeded(ctx context.Context, instance runtimeobject.RuntimeObject, s subroutine.Subroutine, result ctrl.Result, readonly bool, cl client.Client) errors.OperatorError {
+// removeFinalizerIfNeeded removes the finalizers of the subroutine and the given legacy finalizers migrating to them
+func removeFinalizerIfNeeded(ctx context.Context, instance runtimeobject.RuntimeObject, s subroutine.Subroutine, result ctrl.Result, readonly bool, cl client.Client, legacyFinalizers ...string) errors.OperatorError {
 	if readonly {
 		return nil
 	}
@@ -263,7 +442,7 @@ func removeFinalizerIfNeeded(ctx context.Context, instance runtimeobject.Runtime
 	if result.RequeueAfter == 0 {
 		update := false
 		original := instance.DeepCopyObject().(client.Object)
-		for _, f := range s.Finalizers(instance) {
+		for _, f := range slices.Concat(s.Finalizers(instance), legacyFinalizers) {
 			needsUpdate := controllerutil.RemoveFinalizer(instance, f)
 			if needsUpdate {
 				update = true
@@ -281,36 +460,30 @@ func removeFinalizerIfNeeded(ctx context.Context, instance runtimeobject.Runtime
 }
 
 func removeTerminator(ctx context.Context, instance runtimeobject.RuntimeObject, cl client.Client, terminator string) error {
-	if terminator == "" {
-		return nil
-	}
-
-	original := instance.DeepCopyObject().(client.Object)
-
-	currentUn, err := runtime.DefaultUnstructuredConverter.ToUnstructured(instance)
-	if err != nil {
-		return fmt.Errorf("failed to convert instance to unstructured: %w", err)
+	if t, ok := instance.(api.RuntimeObjectTerminators); ok {
+		return removeStatusEntry(ctx, instance, cl, terminator, t.GetTerminators, t.SetTerminators)
 	}
+	return removeUnstructuredStatusEntry(ctx, instance, cl, terminator, "terminators")
+}
 
-	terminators, ok, err := unstructured.NestedStringSlice(currentUn, "status", "terminators")
-	if err != nil || !ok || len(terminators) == 0 {
-		return nil
+func removeInitializer(ctx context.Context, instance runtimeobject.RuntimeObject, cl client.Client, initializer string) error {
+	if i, ok := instance.(api.RuntimeObjectInitializers); ok {
+		return removeStatusEntry(ctx, instance, cl, initializer, i.GetInitializers, i.SetInitializers)
 	}
+	return removeUnstructuredStatusEntry(ctx, instance, cl, initializer, "initializers")
+}
 
-	newTerminators := slices.DeleteFunc(terminators, func(t string) bool {
-		return t == terminator
-	})
-	if len(newTerminators) == len(terminators) {
+// removeStatusEntry removes the entry from the terminators or initializers of instances implementing the typed
+// interfaces and patches the status if the entry was present
+func removeStatusEntry(ctx context.Context, instance runtimeobject.RuntimeObject, cl client.Client, entry string, get func() []string, set func([]string)) error {
+	if entry == "" || !slices.Contains(get(), entry) {
 		return nil
 	}
 
-	if err := unstructured.SetNestedStringSlice(currentUn, newTerminators, "status", "terminators"); err != nil {
-		return fmt.Errorf("failed to set terminators: %w", err)
-	}
-
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(currentUn, instance); err != nil {
-		return fmt.Errorf("failed to convert unstructured to instance: %w", err)
-	}
+	original := instance.DeepCopyObject().(client.Object)
+	set(slices.DeleteFunc(slices.Clone(get()), func(e string) bool {
+		return e == entry
+	}))
 
 	if err := cl.Status().Patch(ctx, instance.(client.Object), client.MergeFrom(original)); err != nil {
 		return fmt.Errorf("failed to patch instance status: %w", err)
@@ -319,8 +492,10 @@ func removeTerminator(ctx context.Context, instance runtimeobject.RuntimeObject,
 	return nil
 }
 
-func removeInitializer(ctx context.Context, instance runtimeobject.RuntimeObject, cl client.Client, initializer string) error {
-	if initializer == "" {
+// removeUnstructuredStatusEntry removes the entry from the string slice at status.<field> of instances not
+// implementing the typed interfaces, e.g. kcp LogicalClusters
+func removeUnstructuredStatusEntry(ctx context.Context, instance runtimeobject.RuntimeObject, cl client.Client, entry string, field string) error {
+	if entry == "" {
 		return nil
 	}
 
@@ -331,20 +506,20 @@ func removeInitializer(ctx context.Context, instance runtimeobject.RuntimeObject
 		return fmt.Errorf("failed to convert instance to unstructured: %w", err)
 	}
 
-	initializers, ok, err := unstructured.NestedStringSlice(currentUn, "status", "initializers")
-	if err != nil || !ok || len(initializers) == 0 {
+	entries, ok, err := unstructured.NestedStringSlice(currentUn, "status", field)
+	if err != nil || !ok || len(entries) == 0 {
 		return nil
 	}
 
-	newInitializers := slices.DeleteFunc(initializers, func(i string) bool {
-		return i == initializer
+	newEntries := slices.DeleteFunc(entries, func(e string) bool {
+		return e == entry
 	})
-	if len(newInitializers) == len(initializers) {
+	if len(newEntries) == len(entries) {
 		return nil
 	}
 
-	if err := unstructured.SetNestedStringSlice(currentUn, newInitializers, "status", "initializers"); err != nil {
-		return fmt.Errorf("failed to set initializers: %w", err)
+	if err := unstructured.SetNestedStringSlice(currentUn, newEntries, "status", field); err != nil {
+		return fmt.Errorf("failed to set %s: %w", field, err)
 	}
 
 	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(currentUn, instance); err != nil {
@@ -487,5 +662,107 @@ func ValidateInterfaces(instance runtimeobject.RuntimeObject, log *logger.Logger
 			return err
 		}
 	}
+	if r, ok := l.(api.ReportingLifecycle); ok && r.ReportManager() != nil {
+		_, err := util.ToInterface[api.RuntimeObjectSubroutineReports](instance, log)
+		if err != nil {
+			return err
+		}
+	}
+	if h, ok := l.(api.InputHashingLifecycle); ok && h.SkipUnchangedSubroutines() {
+		_, err := util.ToInterface[api.RuntimeObjectSubroutineInputHashes](instance, log)
+		if err != nil {
+			return err
+		}
+	}
+	for _, s := range l.Subroutines() {
+		if c, ok := s.(subroutine.FinalizeCheckpointer); !ok || !c.CheckpointsFinalization() {
+			continue
+		}
+		if _, ok := instance.(api.RuntimeObjectFinalizeCheckpoints); !ok {
+			return fmt.Errorf("instance of type %T does not support the finalize checkpoints of subroutine %s, it has to implement api.RuntimeObjectFinalizeCheckpoints", instance, s.GetName())
+		}
+	}
+	if t, ok := l.(api.TerminatingLifecycle); ok && t.Terminator() != "" {
+		if _, ok := instance.(api.RuntimeObjectTerminators); !ok && !hasStatusField(instance, "terminators") {
+			return fmt.Errorf("instance of type %T does not support terminators, it has to implement api.RuntimeObjectTerminators", instance)
+		}
+	}
+	if i, ok := l.(api.InitializingLifecycle); ok && i.Initializer() != "" {
+		if _, ok := instance.(api.RuntimeObjectInitializers); !ok && !hasStatusField(instance, "initializers") {
+			return fmt.Errorf("instance of type %T does not support initializers, it has to implement api.RuntimeObjectInitializers", instance)
+		}
+	}
+	return nil
+}
+
+// hasStatusField returns whether the status of the instance has a field with the json name. Instances not
+// implementing the typed interfaces, e.g. kcp LogicalClusters, are supported if the field exists.
+func hasStatusField(instance runtimeobject.RuntimeObject, name string) bool {
+	if _, ok := instance.(runtime.Unstructured); ok {
+		return true
+	}
+	status, ok := jsonField(reflect.TypeOf(instance), "status")
+	if !ok {
+		return false
+	}
+	_, ok = jsonField(status.Type, name)
+	return ok
+}
+
+// jsonField returns the field of the struct with the json name, including fields of inlined structs
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && tag == "" {
+			if inlined, ok := jsonField(field.Type, name); ok {
+				return inlined, true
+			}
+			continue
+		}
+		if tag == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// CollectWatches returns the validated watches of all subroutines implementing subroutine.Watcher
+func CollectWatches(l api.Lifecycle) ([]watch.Watch, error) {
+	var watches []watch.Watch
+	for _, s := range l.Subroutines() {
+		w, ok := s.(subroutine.Watcher)
+		if !ok {
+			continue
+		}
+		for _, sw := range w.Watches() {
+			if err := sw.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid watch of subroutine %s: %w", s.GetName(), err)
+			}
+			watches = append(watches, sw)
+		}
+	}
+	return watches, nil
+}
+
+// IndexWatchedFields registers the field indexes on the primary object required by watches mapping by indexed field.
+// Indexes with the same field name are only registered once.
+func IndexWatchedFields(ctx context.Context, indexer client.FieldIndexer, instance runtimeobject.RuntimeObject, watches []watch.Watch) error {
+	indexed := map[string]bool{}
+	for _, w := range watches {
+		if w.Mapping != watch.MapByIndexedField || indexed[w.IndexField] {
+			continue
+		}
+		if err := indexer.IndexField(ctx, instance, w.IndexField, w.IndexFunc); err != nil {
+			return fmt.Errorf("failed to index field %s: %w", w.IndexField, err)
+		}
+		indexed[w.IndexField] = true
+	}
 	return nil
 }
@@ -12,15 +12,19 @@ import (
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	pmerrors "github.com/platform-mesh/golang-commons/controller/errors"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/conditions"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/mocks"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/priority"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
 	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/controller/testSupport/fault"
 	operrors "github.com/platform-mesh/golang-commons/errors"
 	"github.com/platform-mesh/golang-commons/logger"
 	"github.com/platform-mesh/golang-commons/logger/testlogger"
@@ -444,6 +448,40 @@ func TestLifecycle(t *testing.T) {
 		assert.Equal(t, int64(1), instance.Status.ObservedGeneration)
 		assert.GreaterOrEqual(t, 12*time.Hour, result.RequeueAfter)
 	})
+	t.Run("Lifecycle with spread reconciles requeues skipped reconciles with the low priority of the queue policy", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.ImplementingSpreadReconciles{
+			TestApiObject: pmtesting.TestApiObject{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       name,
+					Namespace:  namespace,
+					Generation: 1,
+				},
+				Status: pmtesting.TestStatus{
+					Some:               "string",
+					ObservedGeneration: 1,
+					NextReconcileTime:  metav1.NewTime(time.Now().Add(1 * time.Hour)),
+				},
+			},
+		}
+
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+
+		policy, err := priority.NewPolicy(priority.WithLowPriority(-10))
+		require.NoError(t, err)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, ShouldReconcile: false, SubroutinesArr: []subroutine.Subroutine{}}
+		mgr.WithQueuePolicy(policy)
+		mgr.WithSpreadingReconciles()
+
+		// Act
+		result, err := Reconcile(ctx, request.NamespacedName, instance, fakeClient, mgr)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 10*time.Minute, result.RequeueAfter)
+		require.NotNil(t, result.Priority)
+		assert.Equal(t, -10, *result.Priority)
+	})
 	t.Run("Lifecycle with spread reconciles and processing fails (no-retry)", func(t *testing.T) {
 		// Arrange
 		instance := &pmtesting.ImplementingSpreadReconciles{
@@ -815,16 +853,13 @@ func TestLifecycle(t *testing.T) {
 	t.Run("Lifecycle with spread reconciles and manage conditions and processing fails (no-retry)", func(t *testing.T) {
 		// Arrange
 		instance := &pmtesting.ImplementConditionsAndSpreadReconciles{
-			TestApiObject: pmtesting.TestApiObject{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:       name,
-					Namespace:  namespace,
-					Generation: 1,
-				},
-				Status: pmtesting.TestStatus{
-					Some:               "string",
-					ObservedGeneration: 0,
-				},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       name,
+				Namespace:  namespace,
+				Generation: 1,
+			},
+			Status: pmtesting.CommonStatusApiObjectStatus{
+				Some: "string",
 			},
 		}
 
@@ -1170,3 +1205,233 @@ func TestValidateInterfaces(t *testing.T) {
 	err := ValidateInterfaces(instance, log, mgr)
 	assert.NoError(t, err)
 }
+
+func TestValidateInterfacesFinalizeCheckpoints(t *testing.T) {
+	log := testlogger.New().Logger
+	mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{&pmtesting.CheckpointingSubroutine{}}}
+
+	t.Run("accepts instances persisting checkpoints", func(t *testing.T) {
+		assert.NoError(t, ValidateInterfaces(&pmtesting.ImplementFinalizeCheckpoints{}, log, mgr))
+	})
+
+	t.Run("rejects instances without checkpoints", func(t *testing.T) {
+		err := ValidateInterfaces(&pmtesting.TestApiObject{}, log, mgr)
+		assert.EqualError(t, err, "instance of type *testSupport.TestApiObject does not support the finalize checkpoints of subroutine checkpointing, it has to implement api.RuntimeObjectFinalizeCheckpoints")
+	})
+}
+
+func TestValidateInterfacesTerminatorsAndInitializers(t *testing.T) {
+	log := testlogger.New().Logger
+
+	t.Run("accepts typed instances", func(t *testing.T) {
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log}).WithTerminator("root:terminator").WithInitializer("root:initializer")
+		assert.NoError(t, ValidateInterfaces(&pmtesting.CommonStatusApiObject{}, log, mgr))
+	})
+
+	t.Run("accepts instances with status fields", func(t *testing.T) {
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log}).WithTerminator("root:terminator").WithInitializer("root:initializer")
+		assert.NoError(t, ValidateInterfaces(&pmtesting.ImplementingSpreadReconciles{}, log, mgr))
+	})
+
+	t.Run("rejects instances without terminators", func(t *testing.T) {
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log}).WithTerminator("root:terminator")
+		err := ValidateInterfaces(&pmtesting.TestNoStatusApiObject{}, log, mgr)
+		assert.EqualError(t, err, "instance of type *testSupport.TestNoStatusApiObject does not support terminators, it has to implement api.RuntimeObjectTerminators")
+	})
+
+	t.Run("rejects instances without initializers", func(t *testing.T) {
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log}).WithInitializer("root:initializer")
+		err := ValidateInterfaces(&pmtesting.TestNoStatusApiObject{}, log, mgr)
+		assert.EqualError(t, err, "instance of type *testSupport.TestNoStatusApiObject does not support initializers, it has to implement api.RuntimeObjectInitializers")
+	})
+}
+
+func TestReconcileTypedTerminatorsAndInitializers(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New().Logger
+
+	t.Run("removes the terminator when in deletion", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.CommonStatusApiObject{ObjectMeta: metav1.ObjectMeta{
+			Name: nName.Name, Namespace: nName.Namespace, DeletionTimestamp: &metav1.Time{Time: time.Now()}, Finalizers: []string{"test-keeping-finalizer"},
+		}}
+		instance.SetTerminators([]string{"root:terminator", "other:terminator"})
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log}).WithTerminator("root:terminator")
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		persisted := &pmtesting.CommonStatusApiObject{}
+		require.NoError(t, fakeClient.Get(ctx, nName, persisted))
+		assert.Equal(t, []string{"other:terminator"}, persisted.GetTerminators())
+	})
+
+	t.Run("removes the initializer when not in deletion", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.CommonStatusApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+		instance.SetInitializers([]string{"root:initializer"})
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log}).WithInitializer("root:initializer")
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		persisted := &pmtesting.CommonStatusApiObject{}
+		require.NoError(t, fakeClient.Get(ctx, nName, persisted))
+		assert.Empty(t, persisted.GetInitializers())
+	})
+}
+
+type classifyingSubroutine struct {
+	err error
+}
+
+func (s classifyingSubroutine) Process(context.Context, runtimeobject.RuntimeObject) (ctrl.Result, operrors.OperatorError) {
+	return ctrl.Result{}, pmerrors.Classify(s.err)
+}
+
+func (s classifyingSubroutine) Finalize(context.Context, runtimeobject.RuntimeObject) (ctrl.Result, operrors.OperatorError) {
+	return ctrl.Result{}, nil
+}
+
+func (s classifyingSubroutine) GetName() string { return "classifyingSubroutine" }
+
+func (s classifyingSubroutine) Finalizers(runtimeobject.RuntimeObject) []string { return nil }
+
+func TestReconcileClassifiedErrors(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New().Logger
+
+	t.Run("requeues after the delay of the classified error", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		notFound := errors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "dependency")
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{classifyingSubroutine{err: pmerrors.Dependency(notFound)}}}
+
+		// Act
+		result, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 10*time.Second, result.RequeueAfter)
+	})
+
+	t.Run("returns classified errors without delay", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{classifyingSubroutine{err: context.DeadlineExceeded}}}
+
+		// Act
+		result, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, ctrl.Result{}, result)
+	})
+
+	t.Run("returns NotFound errors of other objects than dependencies", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		notFound := errors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "deleted")
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{classifyingSubroutine{err: notFound}}}
+
+		// Act
+		result, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		assert.True(t, errors.IsNotFound(err))
+		assert.Equal(t, ctrl.Result{}, result)
+	})
+}
+
+func TestReconcileSubroutinePanic(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New().Logger
+
+	t.Run("recovers the panic and persists the status", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		injector, err := fault.NewInjector(fault.WithRules(fault.Rule{Match: []fault.Matcher{fault.OnSubroutine("ContextValueSubroutine")}, Fault: fault.Panic("boom")}))
+		require.NoError(t, err)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{
+			pmtesting.ChangeStatusSubroutine{Client: fakeClient},
+			injector.Subroutine(pmtesting.ContextValueSubroutine{}),
+			pmtesting.FinalizerSubroutine{Client: fakeClient},
+		}}
+		tracker := &recordingTracker{}
+		mgr.WithReconcileTracker(tracker)
+
+		// Act
+		result, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.Error(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+		var panicErr *pmerrors.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "ContextValueSubroutine", panicErr.Subroutine)
+		assert.Equal(t, "boom", panicErr.Value)
+		assert.Contains(t, string(panicErr.Stack), "runtime/debug.Stack")
+		assert.Equal(t, []string{"changeStatus", "ContextValueSubroutine"}, tracker.subroutines)
+		persisted := &pmtesting.TestApiObject{}
+		require.NoError(t, fakeClient.Get(ctx, nName, persisted))
+		assert.Equal(t, "other string", persisted.Status.Some)
+	})
+
+	t.Run("recovers the panic of a finalizing subroutine", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{
+			Name: nName.Name, Namespace: nName.Namespace, DeletionTimestamp: &metav1.Time{Time: time.Now()}, Finalizers: []string{pmtesting.SubroutineFinalizer},
+		}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		injector, err := fault.NewInjector(fault.WithRules(fault.Rule{Match: []fault.Matcher{fault.OnPhase(fault.PhaseFinalize)}, Fault: fault.Panic(goerrors.New("finalize failed"))}))
+		require.NoError(t, err)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: []subroutine.Subroutine{injector.Subroutine(pmtesting.FinalizerSubroutine{Client: fakeClient})}}
+
+		// Act
+		_, err = Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		assert.EqualError(t, err, "subroutine changeStatus panicked: finalize failed")
+		assert.True(t, pmerrors.IsPanic(err))
+		persisted := &pmtesting.TestApiObject{}
+		require.NoError(t, fakeClient.Get(ctx, nName, persisted))
+		assert.Equal(t, []string{pmtesting.SubroutineFinalizer}, persisted.Finalizers)
+	})
+}
+
+func TestReconcileCommonStatus(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New().Logger
+	instance := &pmtesting.CommonStatusApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace, Generation: 1}}
+	fakeClient := pmtesting.CreateFakeClient(t, instance)
+	mgr := &pmtesting.TestLifecycleManager{Logger: log, ShouldReconcile: true, SubroutinesArr: []subroutine.Subroutine{pmtesting.AddConditionSubroutine{Ready: metav1.ConditionTrue}}}
+	mgr.WithSpreadingReconciles()
+	mgr.WithConditionManagement()
+	require.NoError(t, ValidateInterfaces(instance, log, mgr))
+
+	// Act
+	_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+	// Assert
+	require.NoError(t, err)
+	persisted := &pmtesting.CommonStatusApiObject{}
+	require.NoError(t, fakeClient.Get(ctx, nName, persisted))
+	assert.Equal(t, int64(1), persisted.Status.ObservedGeneration)
+	assert.False(t, persisted.Status.NextReconcileTime.IsZero())
+	assert.NotEmpty(t, persisted.Status.Conditions)
+}
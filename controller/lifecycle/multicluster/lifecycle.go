@@ -4,26 +4,39 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	mcbuilder "sigs.k8s.io/multicluster-runtime/pkg/builder"
+	mchandler "sigs.k8s.io/multicluster-runtime/pkg/handler"
 	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
 	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
 
+	"k8s.io/client-go/tools/events"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
 
+	pmclient "github.com/platform-mesh/golang-commons/controller/client"
 	"github.com/platform-mesh/golang-commons/controller/filter"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/conditions"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/priority"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/ratelimiter"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/report"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/sharding"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/spread"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/trigger"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/watch"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/watchdog"
 	"github.com/platform-mesh/golang-commons/logger"
 )
 
@@ -38,7 +51,18 @@ type LifecycleManager struct {
 	subroutines        []subroutine.Subroutine
 	spreader           *spread.Spreader
 	conditionsManager  *conditions.ConditionManager
+	reportManager      *report.ReportManager
+	skipUnchanged      bool
+	protectionRequeue  time.Duration
+	instrumentation    *pmclient.Instrumentation
+	watchdog           *watchdog.Watchdog
+	sharder            *sharding.Sharder
+	debugClaims        *filter.Claims
+	trigger            *trigger.Trigger
+	queuePolicy        *priority.Policy
 	prepareContextFunc api.PrepareContextFunc
+	legacyFinalizers   []api.LegacyFinalizer
+	eventRecorder      events.EventRecorder
 	rateLimiter        workqueue.TypedRateLimiter[mcreconcile.Request]
 	terminator         string
 	initializer        string
@@ -82,6 +106,46 @@ func (l *LifecycleManager) Spreader() api.SpreadManager { // it is important to
 	}
 	return l.spreader
 }
+func (l *LifecycleManager) ReportManager() api.ReportManager {
+	// it is important to return nil instead of a nil pointer to the interface to avoid misbehaving nil checks
+	if l.reportManager == nil {
+		return nil
+	}
+	return l.reportManager
+}
+func (l *LifecycleManager) SkipUnchangedSubroutines() bool {
+	return l.skipUnchanged
+}
+func (l *LifecycleManager) DeletionBlockedRequeueAfter() time.Duration {
+	return l.protectionRequeue
+}
+func (l *LifecycleManager) ClientInstrumentation() api.ClientInstrumentation {
+	// it is important to return nil instead of a nil pointer to the interface to avoid misbehaving nil checks
+	if l.instrumentation == nil {
+		return nil
+	}
+	return l.instrumentation
+}
+func (l *LifecycleManager) ReconcileTracker() api.ReconcileTracker {
+	// it is important to return nil instead of a nil pointer to the interface to avoid misbehaving nil checks
+	if l.watchdog == nil {
+		return nil
+	}
+	return l.watchdog
+}
+func (l *LifecycleManager) QueuePolicy() api.QueuePolicy {
+	// it is important to return nil instead of a nil pointer to the interface to avoid misbehaving nil checks
+	if l.queuePolicy == nil {
+		return nil
+	}
+	return l.queuePolicy
+}
+func (l *LifecycleManager) LegacyFinalizers() []api.LegacyFinalizer {
+	return l.legacyFinalizers
+}
+func (l *LifecycleManager) EventRecorder() events.EventRecorder {
+	return l.eventRecorder
+}
 
 func (l *LifecycleManager) Terminator() string {
 	return l.terminator
@@ -92,6 +156,10 @@ func (l *LifecycleManager) Initializer() string {
 }
 
 func (l *LifecycleManager) Reconcile(ctx context.Context, req mcreconcile.Request, instance runtimeobject.RuntimeObject) (ctrl.Result, error) {
+	if l.sharder != nil && !l.sharder.Owns(req.ClusterName) {
+		l.log.Debug().Str("cluster", req.ClusterName).Msg("skipping reconcile, cluster is owned by another shard")
+		return ctrl.Result{}, nil
+	}
 	cl, err := l.mgr.GetCluster(ctx, req.ClusterName)
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to get cluster: %w", err)
@@ -103,9 +171,18 @@ func (l *LifecycleManager) SetupWithManagerBuilder(mgr mcmanager.Manager, maxRec
 	if err := lifecycle.ValidateInterfaces(instance, log, l); err != nil {
 		return nil, err
 	}
+	if _, err := lifecycle.FinalizeOrder(l.Subroutines()); err != nil {
+		return nil, err
+	}
 
-	if (l.ConditionsManager() != nil || l.Spreader() != nil) && l.Config().ReadOnly {
-		return nil, fmt.Errorf("cannot use conditions or spread reconciles in read-only mode")
+	if (l.ConditionsManager() != nil || l.Spreader() != nil || l.ReportManager() != nil) && l.Config().ReadOnly {
+		return nil, fmt.Errorf("cannot use conditions, spread reconciles or subroutine reports in read-only mode")
+	}
+	if l.debugClaims != nil {
+		return nil, fmt.Errorf("debug claims are not supported by the multicluster lifecycle manager, they are not scoped to a cluster")
+	}
+	if l.Config().ReadOnly {
+		log.Warn().Msg("read-only mode only guards the clients subroutines load with client.LoadClientFromContext, clients injected into subroutines can still write")
 	}
 
 	eventPredicates = append([]predicate.Predicate{filter.DebugResourcesBehaviourPredicate(debugLabelValue)}, eventPredicates...)
@@ -116,12 +193,69 @@ func (l *LifecycleManager) SetupWithManagerBuilder(mgr mcmanager.Manager, maxRec
 	if l.rateLimiter != nil {
 		opts.RateLimiter = l.rateLimiter
 	}
+	if l.queuePolicy != nil {
+		opts.UsePriorityQueue = ptr.To(true)
+	}
+	if l.sharder != nil {
+		usePriorityQueue := ptr.Deref(opts.UsePriorityQueue, ptr.Deref(mgr.GetLocalManager().GetControllerOptions().UsePriorityQueue, true))
+		opts.NewQueue = l.sharder.NewClusterQueue(usePriorityQueue)
+	}
 
-	return mcbuilder.ControllerManagedBy(mgr).
+	b := mcbuilder.ControllerManagedBy(mgr).
 		Named(reconcilerName).
 		For(instance).
 		WithOptions(opts).
-		WithEventFilter(predicate.And(eventPredicates...)), nil
+		WithEventFilter(predicate.And(eventPredicates...))
+
+	if l.queuePolicy != nil {
+		b.Watches(instance, l.queuePolicy.ClusterHandler(l.config.ControllerName))
+	}
+	if err := l.setupWatches(mgr, b, instance, log); err != nil {
+		return nil, err
+	}
+	if l.sharder != nil {
+		src, err := l.sharder.ClusterResyncSource(mgr.GetLocalManager().GetScheme(), instance)
+		if err != nil {
+			return nil, err
+		}
+		b.WatchesRawSource(src)
+	}
+	if l.trigger != nil {
+		b.WatchesRawSource(l.trigger.ClusterSource())
+	}
+	return b, nil
+}
+
+// setupWatches registers the watches and field indexes declared by subroutines implementing subroutine.Watcher
+// on all engaged clusters
+func (l *LifecycleManager) setupWatches(mgr mcmanager.Manager, b *mcbuilder.Builder, instance runtimeobject.RuntimeObject, log *logger.Logger) error {
+	watches, err := lifecycle.CollectWatches(l)
+	if err != nil {
+		return err
+	}
+	if err := lifecycle.IndexWatchedFields(context.Background(), mgr.GetFieldIndexer(), instance, watches); err != nil {
+		return err
+	}
+
+	for _, w := range watches {
+		if w.Mapping == watch.MapByOwnerReference {
+			b.Owns(w.Object, mcbuilder.WithPredicates(w.Predicates...))
+			continue
+		}
+		// resolve the mapping against the local scheme once to fail early on unregistered types
+		if _, err := w.MapFunc(nil, mgr.GetLocalManager().GetScheme(), instance, log); err != nil {
+			return err
+		}
+		b.Watches(w.Object, func(clusterName string, cl cluster.Cluster) handler.TypedEventHandler[client.Object, mcreconcile.Request] {
+			mapFunc, err := w.MapFunc(cl.GetClient(), cl.GetScheme(), instance, log.MustChildLoggerWithAttributes("cluster", clusterName))
+			if err != nil {
+				log.Error().Err(err).Str("cluster", clusterName).Msg("failed to create map func for watch")
+				mapFunc = func(context.Context, client.Object) []reconcile.Request { return nil }
+			}
+			return mchandler.Lift(handler.EnqueueRequestsFromMapFunc(mapFunc))(clusterName, cl)
+		}, mcbuilder.WithPredicates(w.Predicates...))
+	}
+	return nil
 }
 func (l *LifecycleManager) SetupWithManager(mgr mcmanager.Manager, maxReconciles int, reconcilerName string, instance runtimeobject.RuntimeObject, debugLabelValue string, r mcreconcile.Reconciler, log *logger.Logger, eventPredicates ...predicate.Predicate) error {
 	b, err := l.SetupWithManagerBuilder(mgr, maxReconciles, reconcilerName, instance, debugLabelValue, log, eventPredicates...)
@@ -141,12 +275,24 @@ func (l *LifecycleManager) WithPrepareContextFunc(prepareFunction api.PrepareCon
 }
 
 // WithReadOnly allows to set the controller to read-only mode
-// In read-only mode, the controller will not update the status of the instance
+// In read-only mode, the controller will not update the status of the instance and writes of subroutines made with
+// the client from the context are rejected
+// Clients injected into subroutines at construction are not guarded, subroutines have to load their client with
+// client.LoadClientFromContext or be constructed with a client.NewReadOnlyClient
 func (l *LifecycleManager) WithReadOnly() *LifecycleManager {
 	l.config.ReadOnly = true
 	return l
 }
 
+// WithReadOnlyDryRun allows to set the controller to read-only mode, sending the writes of subroutines
+// made with the client from the context as dry run instead of rejecting them
+// Clients injected into subroutines at construction are not guarded, see WithReadOnly
+func (l *LifecycleManager) WithReadOnlyDryRun() *LifecycleManager {
+	l.config.ReadOnly = true
+	l.config.ReadOnlyDryRun = true
+	return l
+}
+
 // WithSpreadingReconciles sets the LifecycleManager to spread out the reconciles
 func (l *LifecycleManager) WithSpreadingReconciles() api.Lifecycle {
 	l.spreader = spread.NewSpreader()
@@ -176,3 +322,81 @@ func (l *LifecycleManager) WithInitializer(initializer string) *LifecycleManager
 	l.initializer = initializer
 	return l
 }
+
+// WithLegacyFinalizers allows to configure finalizers which are no longer owned by any subroutine
+// These finalizers are removed from instances or migrated to the finalizer configured in MigrateTo
+func (l *LifecycleManager) WithLegacyFinalizers(finalizers ...api.LegacyFinalizer) *LifecycleManager {
+	l.legacyFinalizers = append(l.legacyFinalizers, finalizers...)
+	return l
+}
+
+// WithEventRecorder allows to set a recorder used to emit events for the reconciled instances
+func (l *LifecycleManager) WithEventRecorder(recorder events.EventRecorder) *LifecycleManager {
+	l.eventRecorder = recorder
+	return l
+}
+
+// WithSubroutineReports allows to persist a report per subroutine in the status of the instance
+// Runs with an unchanged outcome are recorded at most once per minInterval to avoid status write storms
+func (l *LifecycleManager) WithSubroutineReports(minInterval time.Duration) *LifecycleManager {
+	l.reportManager = report.NewReportManager(minInterval)
+	return l
+}
+
+// WithClientInstrumentation allows to pass a client instrumented with spans, metrics and slow call logs to the
+// subroutines via the context, labelled with the subroutine name. Subroutines load it with client.LoadClientFromContext
+func (l *LifecycleManager) WithClientInstrumentation(i *pmclient.Instrumentation) *LifecycleManager {
+	l.instrumentation = i
+	return l
+}
+
+// WithSkipUnchangedSubroutines allows to skip subroutines implementing subroutine.InputHasher as long as their input
+// hash and the generation are unchanged since their last successful run. The refresh label forces processing.
+func (l *LifecycleManager) WithSkipUnchangedSubroutines() *LifecycleManager {
+	l.skipUnchanged = true
+	return l
+}
+
+// WithDebugClaims is not supported by the multicluster lifecycle manager, as claims are not scoped to a cluster
+// SetupWithManagerBuilder returns an error if claims are set
+func (l *LifecycleManager) WithDebugClaims(c *filter.Claims) *LifecycleManager {
+	l.debugClaims = c
+	return l
+}
+
+// WithDeletionBlockedRequeueAfter allows to configure the delay after which a deletion blocked by deletion protection
+// is checked again, defaults to lifecycle.DefaultDeletionBlockedRequeueAfter
+func (l *LifecycleManager) WithDeletionBlockedRequeueAfter(d time.Duration) *LifecycleManager {
+	l.protectionRequeue = d
+	return l
+}
+
+// WithSharding allows to partition the engaged clusters among the replicas of the shard group of the sharder
+// Requests for clusters owned by other replicas are skipped and the clusters of a replica are resynced on a rebalance
+// The sharder needs to be added to the multicluster manager separately and leader election should be disabled
+func (l *LifecycleManager) WithSharding(s *sharding.Sharder) *LifecycleManager {
+	l.sharder = s
+	return l
+}
+
+// WithWatchdog allows to track in-flight reconciles with the given watchdog to detect reconciles stuck in a subroutine
+// The watchdog needs to be added to the manager and its Checker registered as health check separately
+func (l *LifecycleManager) WithWatchdog(w *watchdog.Watchdog) *LifecycleManager {
+	l.watchdog = w
+	return l
+}
+
+// WithTrigger allows to enqueue reconciles on demand with requests to the HTTP endpoint of the trigger
+// The trigger needs to be added to the manager separately
+func (l *LifecycleManager) WithTrigger(t *trigger.Trigger) *LifecycleManager {
+	l.trigger = t
+	return l
+}
+
+// WithPriorityQueue allows to prioritize reconciles with the policy in controller-runtime's priority queue
+// Generation changes, deletions and refresh requests are reconciled before other events and spread-driven resyncs
+// are reconciled last
+func (l *LifecycleManager) WithPriorityQueue(p *priority.Policy) *LifecycleManager {
+	l.queuePolicy = p
+	return l
+}
@@ -16,11 +16,18 @@ import (
 	"k8s.io/client-go/rest"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
 	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
 
+	"github.com/platform-mesh/golang-commons/controller/filter"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/priority"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/sharding"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/trigger"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/watch"
 	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
 	operrors "github.com/platform-mesh/golang-commons/errors"
 	"github.com/platform-mesh/golang-commons/logger/testlogger"
@@ -59,6 +66,173 @@ func TestLifecycle(t *testing.T) {
 		// Assert
 		assert.NoError(t, err)
 	})
+	t.Run("Should setup with manager with subroutine watches", func(t *testing.T) {
+		// Arrange
+		instance := &v1.Namespace{}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+
+		mgr, log := createLifecycleManager([]subroutine.Subroutine{pmtesting.WatchingSubroutine{WatchList: []watch.Watch{
+			watch.Owns(&v1.ConfigMap{}),
+			watch.ByLabel(&v1.Secret{}, "owner", ""),
+			watch.ByIndexedField(&v1.ServiceAccount{}, "serviceAccount", func(o client.Object) []string { return []string{o.GetName()} }, func(o client.Object) []string { return []string{o.GetName()} }),
+		}}}, fakeClient)
+
+		tr := &testReconciler{
+			lifecycleManager: mgr,
+		}
+
+		// Act
+		cfg := &rest.Config{}
+		provider := pmtesting.NewFakeProvider(cfg)
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		mmanager, err := mcmanager.New(cfg, provider, mcmanager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+		err = mgr.SetupWithManager(mmanager, 0, "testReconcilerWithWatches", instance, "test", tr, log.Logger)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+	t.Run("Should setup with manager with sharding", func(t *testing.T) {
+		// Arrange
+		instance := &v1.Namespace{}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+
+		mgr, log := createLifecycleManager([]subroutine.Subroutine{}, fakeClient)
+		sharder, err := sharding.NewSharder(fake.NewClientBuilder().Build(), "default", "test-operator", log.Logger, sharding.WithIdentity("a"))
+		assert.NoError(t, err)
+		mgr.WithSharding(sharder)
+		tr := &testReconciler{
+			lifecycleManager: mgr,
+		}
+
+		// Act
+		cfg := &rest.Config{}
+		provider := pmtesting.NewFakeProvider(cfg)
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		mmanager, err := mcmanager.New(cfg, provider, mcmanager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+		err = mgr.SetupWithManager(mmanager, 0, "testReconcilerWithSharding", instance, "test", tr, log.Logger)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+	t.Run("Should setup with manager with trigger", func(t *testing.T) {
+		// Arrange
+		instance := &v1.Namespace{}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+
+		mgr, log := createLifecycleManager([]subroutine.Subroutine{}, fakeClient)
+		trig, err := trigger.NewTrigger(log.Logger, trigger.WithAuthorizer(trigger.AllowSpiffeIDs("spiffe://cluster.local/ns/default/sa/caller")))
+		assert.NoError(t, err)
+		mgr.WithTrigger(trig)
+		tr := &testReconciler{
+			lifecycleManager: mgr,
+		}
+
+		// Act
+		cfg := &rest.Config{}
+		provider := pmtesting.NewFakeProvider(cfg)
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		mmanager, err := mcmanager.New(cfg, provider, mcmanager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+		err = mgr.SetupWithManager(mmanager, 0, "testReconcilerWithTrigger", instance, "test", tr, log.Logger)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+	t.Run("Should setup with manager with priority queue", func(t *testing.T) {
+		// Arrange
+		instance := &v1.Namespace{}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+
+		mgr, log := createLifecycleManager([]subroutine.Subroutine{}, fakeClient)
+		policy, err := priority.NewPolicy()
+		assert.NoError(t, err)
+		mgr.WithPriorityQueue(policy)
+		tr := &testReconciler{
+			lifecycleManager: mgr,
+		}
+
+		// Act
+		cfg := &rest.Config{}
+		provider := pmtesting.NewFakeProvider(cfg)
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		mmanager, err := mcmanager.New(cfg, provider, mcmanager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+		err = mgr.SetupWithManager(mmanager, 0, "testReconcilerWithPriorityQueue", instance, "test", tr, log.Logger)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, policy, mgr.QueuePolicy())
+	})
+	t.Run("Should skip reconciles of clusters owned by other shards", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+
+		mgr, log := createLifecycleManager([]subroutine.Subroutine{pmtesting.ChangeStatusSubroutine{Client: fakeClient}}, fakeClient)
+		sharder, err := sharding.NewSharder(fake.NewClientBuilder().Build(), "default", "test-operator", log.Logger, sharding.WithIdentity("a"))
+		assert.NoError(t, err)
+		mgr.WithSharding(sharder)
+		tr := &testReconciler{lifecycleManager: mgr}
+
+		// Act
+		result, err := tr.Reconcile(ctx, mcreconcile.Request{ClusterName: "cluster", Request: reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"}}})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, controllerruntime.Result{}, result)
+		assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(instance), instance))
+		assert.Empty(t, instance.Status.Some)
+	})
+	t.Run("Should fail setup with invalid subroutine watch", func(t *testing.T) {
+		// Arrange
+		instance := &v1.Namespace{}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+
+		mgr, log := createLifecycleManager([]subroutine.Subroutine{pmtesting.WatchingSubroutine{WatchList: []watch.Watch{
+			watch.ByLabel(&v1.Secret{}, "", ""),
+		}}}, fakeClient)
+
+		// Act
+		cfg := &rest.Config{}
+		provider := pmtesting.NewFakeProvider(cfg)
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		mmanager, err := mcmanager.New(cfg, provider, mcmanager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+		_, err = mgr.SetupWithManagerBuilder(mmanager, 0, "testReconciler", instance, "test", log.Logger)
+
+		// Assert
+		assert.Error(t, err)
+	})
+	t.Run("Should fail setup with debug claims", func(t *testing.T) {
+		// Arrange
+		instance := &v1.Namespace{}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+
+		mgr, log := createLifecycleManager([]subroutine.Subroutine{}, fakeClient)
+		claims, err := filter.NewClaims(fake.NewClientBuilder().Build(), "default", log.Logger)
+		assert.NoError(t, err)
+		mgr.WithDebugClaims(claims)
+
+		// Act
+		cfg := &rest.Config{}
+		provider := pmtesting.NewFakeProvider(cfg)
+		scheme := runtime.NewScheme()
+		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+		mmanager, err := mcmanager.New(cfg, provider, mcmanager.Options{Scheme: scheme})
+		assert.NoError(t, err)
+		_, err = mgr.SetupWithManagerBuilder(mmanager, 0, "testReconciler", instance, "test", log.Logger)
+
+		// Assert
+		assert.EqualError(t, err, "debug claims are not supported by the multicluster lifecycle manager, they are not scoped to a cluster")
+	})
 	t.Run("Should setup with manager not implementing interface", func(t *testing.T) {
 		// Arrange
 		instance := &pmtesting.NotImplementingSpreadReconciles{}
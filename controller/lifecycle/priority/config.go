@@ -0,0 +1,53 @@
+package priority
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+type Config struct {
+	// High is the priority of generation changes, deletions and refresh requests
+	High int
+	// Low is the priority of spread-driven resyncs
+	Low int
+}
+
+var defaultConfig = Config{
+	High: 100,
+	Low:  handler.LowPriority,
+}
+
+func (c Config) validate() error {
+	if c.High <= 0 {
+		return fmt.Errorf("the high priority should be positive")
+	}
+	if c.Low >= 0 {
+		return fmt.Errorf("the low priority should be negative")
+	}
+	return nil
+}
+
+type Option func(*Config)
+
+func WithHighPriority(priority int) Option {
+	return func(c *Config) {
+		c.High = priority
+	}
+}
+
+func WithLowPriority(priority int) Option {
+	return func(c *Config) {
+		c.Low = priority
+	}
+}
+
+func NewConfig(options ...Option) Config {
+	cfg := defaultConfig
+
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	return cfg
+}
@@ -0,0 +1,116 @@
+// Package priority prioritizes the reconciles of lifecycle controllers in controller-runtime's priority queue, so
+// that changes made by users are not queued behind periodic resyncs. Generation changes, deletions and refresh
+// requests of instances are enqueued with a high priority, spread-driven resyncs with a low priority.
+package priority
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	mchandler "sigs.k8s.io/multicluster-runtime/pkg/handler"
+	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/spread"
+)
+
+const (
+	ReasonGeneration = "generation"
+	ReasonDeletion   = "deletion"
+	ReasonRefresh    = "refresh"
+	ReasonResync     = "resync"
+)
+
+var prioritizedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "platform_mesh_queue_prioritized_requests_total",
+	Help: "Number of reconcile requests enqueued with a high or low priority by the queue policy, partitioned by controller, priority and reason",
+}, []string{"controller", "priority", "reason"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(prioritizedRequests)
+}
+
+// Policy assigns the priorities of reconcile requests. The depth of the queue per priority is exposed by
+// controller-runtime as workqueue_depth, the number of prioritized requests as
+// platform_mesh_queue_prioritized_requests_total.
+type Policy struct {
+	cfg Config
+}
+
+func NewPolicy(opts ...Option) (*Policy, error) {
+	cfg := NewConfig(opts...)
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &Policy{cfg: cfg}, nil
+}
+
+// ResyncPriority returns the priority of a spread-driven resync of the controller
+func (p *Policy) ResyncPriority(controller string) *int {
+	prioritizedRequests.WithLabelValues(controller, "low", ReasonResync).Inc()
+	return ptr.To(p.cfg.Low)
+}
+
+// Handler returns a handler for the instances of a controller-runtime controller, raising the priority of requests
+// for generation changes, deletions and refresh requests. It complements the handler of For, which enqueues all
+// other events with the default priority.
+func (p *Policy) Handler(controller string) handler.EventHandler {
+	return newHandler(p, controller, func(obj client.Object) reconcile.Request {
+		return reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}
+	})
+}
+
+// ClusterHandler is the Handler for the instances of a multicluster controller
+func (p *Policy) ClusterHandler(controller string) mchandler.TypedEventHandlerFunc[client.Object, mcreconcile.Request] {
+	return func(clusterName string, _ cluster.Cluster) handler.TypedEventHandler[client.Object, mcreconcile.Request] {
+		return newHandler(p, controller, func(obj client.Object) mcreconcile.Request {
+			return mcreconcile.Request{ClusterName: clusterName, Request: reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}}
+		})
+	}
+}
+
+func newHandler[request comparable](p *Policy, controller string, toRequest func(client.Object) request) handler.TypedEventHandler[client.Object, request] {
+	return handler.TypedFuncs[client.Object, request]{
+		UpdateFunc: func(_ context.Context, e event.TypedUpdateEvent[client.Object], q workqueue.TypedRateLimitingInterface[request]) {
+			reason, ok := Classify(e.ObjectOld, e.ObjectNew)
+			if !ok {
+				return
+			}
+			// Without a priority queue the request is enqueued by the handler of For
+			pq, ok := q.(priorityqueue.PriorityQueue[request])
+			if !ok {
+				return
+			}
+			// The queue keeps the highest priority of a request, regardless of the order the handlers enqueue it
+			pq.AddWithOpts(priorityqueue.AddOpts{Priority: ptr.To(p.cfg.High)}, toRequest(e.ObjectNew))
+			prioritizedRequests.WithLabelValues(controller, "high", reason).Inc()
+		},
+	}
+}
+
+// Classify returns the reason to reconcile an updated instance with a high priority, and false if the update is not
+// prioritized
+func Classify(old, updated client.Object) (string, bool) {
+	if old == nil || updated == nil {
+		return "", false
+	}
+	if old.GetDeletionTimestamp().IsZero() && !updated.GetDeletionTimestamp().IsZero() {
+		return ReasonDeletion, true
+	}
+	if old.GetGeneration() != updated.GetGeneration() {
+		return ReasonGeneration, true
+	}
+	_, refreshed := old.GetLabels()[spread.ReconcileRefreshLabel]
+	if _, refresh := updated.GetLabels()[spread.ReconcileRefreshLabel]; refresh && !refreshed {
+		return ReasonRefresh, true
+	}
+	return "", false
+}
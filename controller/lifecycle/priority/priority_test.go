@@ -0,0 +1,200 @@
+package priority
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/spread"
+)
+
+func TestNewPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr string
+	}{
+		{name: "defaults"},
+		{name: "custom priorities", opts: []Option{WithHighPriority(10), WithLowPriority(-10)}},
+		{name: "high priority not positive", opts: []Option{WithHighPriority(0)}, wantErr: "the high priority should be positive"},
+		{name: "low priority not negative", opts: []Option{WithLowPriority(0)}, wantErr: "the low priority should be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			p, err := NewPolicy(tt.opts...)
+
+			// Assert
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, p)
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	now := metav1.Now()
+	base := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", Generation: 1}}
+
+	tests := []struct {
+		name       string
+		mutate     func(o *corev1.ConfigMap)
+		old        func(o *corev1.ConfigMap)
+		wantReason string
+		wantOk     bool
+	}{
+		{name: "unchanged", mutate: func(o *corev1.ConfigMap) {}},
+		{name: "status or metadata change", mutate: func(o *corev1.ConfigMap) { o.Annotations = map[string]string{"a": "b"} }},
+		{name: "generation change", mutate: func(o *corev1.ConfigMap) { o.Generation = 2 }, wantReason: ReasonGeneration, wantOk: true},
+		{name: "deletion", mutate: func(o *corev1.ConfigMap) {
+			o.DeletionTimestamp = &now
+			o.Generation = 2
+		}, wantReason: ReasonDeletion, wantOk: true},
+		{name: "deletion in progress", old: func(o *corev1.ConfigMap) { o.DeletionTimestamp = &now }, mutate: func(o *corev1.ConfigMap) {
+			o.Finalizers = nil
+		}},
+		{name: "refresh requested", mutate: func(o *corev1.ConfigMap) {
+			o.Labels = map[string]string{spread.ReconcileRefreshLabel: "true"}
+		}, wantReason: ReasonRefresh, wantOk: true},
+		{name: "refresh still requested", old: func(o *corev1.ConfigMap) {
+			o.Labels = map[string]string{spread.ReconcileRefreshLabel: "true"}
+		}, mutate: func(o *corev1.ConfigMap) {}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			old := base.DeepCopy()
+			if tt.old != nil {
+				tt.old(old)
+			}
+			updated := old.DeepCopy()
+			tt.mutate(updated)
+
+			// Act
+			reason, ok := Classify(old, updated)
+
+			// Assert
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	policy, err := NewPolicy()
+	require.NoError(t, err)
+	old := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", Generation: 1, ResourceVersion: "1"}}
+	updated := old.DeepCopy()
+	updated.Generation = 2
+	updated.ResourceVersion = "2"
+	key := types.NamespacedName{Name: "foo", Namespace: "bar"}
+
+	t.Run("enqueues generation changes with a high priority", func(t *testing.T) {
+		// Arrange
+		q := priorityqueue.New[reconcile.Request]("handler-test")
+		defer q.ShutDown()
+		h := policy.Handler("handler-test")
+		before := testutil.ToFloat64(prioritizedRequests.WithLabelValues("handler-test", "high", ReasonGeneration))
+
+		// Act
+		h.Update(context.Background(), event.UpdateEvent{ObjectOld: old, ObjectNew: updated}, q)
+
+		// Assert
+		item, priority, _ := q.GetWithPriority()
+		assert.Equal(t, reconcile.Request{NamespacedName: key}, item)
+		assert.Equal(t, 100, priority)
+		assert.Equal(t, before+1, testutil.ToFloat64(prioritizedRequests.WithLabelValues("handler-test", "high", ReasonGeneration)))
+	})
+
+	t.Run("raises the priority of requests enqueued by other handlers", func(t *testing.T) {
+		// Arrange
+		q := priorityqueue.New[reconcile.Request]("handler-test")
+		defer q.ShutDown()
+		e := event.UpdateEvent{ObjectOld: old, ObjectNew: updated}
+		(&handler.EnqueueRequestForObject{}).Update(context.Background(), e, q)
+
+		// Act
+		policy.Handler("handler-test").Update(context.Background(), e, q)
+
+		// Assert
+		assert.Equal(t, 1, q.Len())
+		_, priority, _ := q.GetWithPriority()
+		assert.Equal(t, 100, priority)
+	})
+
+	t.Run("ignores updates which are not prioritized", func(t *testing.T) {
+		// Arrange
+		q := priorityqueue.New[reconcile.Request]("handler-test")
+		defer q.ShutDown()
+
+		// Act
+		policy.Handler("handler-test").Update(context.Background(), event.UpdateEvent{ObjectOld: old, ObjectNew: old.DeepCopy()}, q)
+
+		// Assert
+		assert.Equal(t, 0, q.Len())
+	})
+
+	t.Run("leaves queues without priorities to the handler of For", func(t *testing.T) {
+		// Arrange
+		q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer q.ShutDown()
+
+		// Act
+		policy.Handler("handler-test").Update(context.Background(), event.UpdateEvent{ObjectOld: old, ObjectNew: updated}, q)
+
+		// Assert
+		assert.Equal(t, 0, q.Len())
+	})
+}
+
+func TestClusterHandler(t *testing.T) {
+	// Arrange
+	policy, err := NewPolicy(WithHighPriority(7))
+	require.NoError(t, err)
+	old := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", Generation: 1}}
+	updated := old.DeepCopy()
+	updated.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	q := priorityqueue.New[mcreconcile.Request]("cluster-handler-test")
+	defer q.ShutDown()
+
+	// Act
+	h := policy.ClusterHandler("cluster-handler-test")("cluster-a", nil)
+	h.Update(context.Background(), event.TypedUpdateEvent[client.Object]{ObjectOld: old, ObjectNew: updated}, q)
+
+	// Assert
+	item, priority, _ := q.GetWithPriority()
+	assert.Equal(t, mcreconcile.Request{ClusterName: "cluster-a", Request: reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"}}}, item)
+	assert.Equal(t, 7, priority)
+}
+
+func TestResyncPriority(t *testing.T) {
+	// Arrange
+	policy, err := NewPolicy()
+	require.NoError(t, err)
+	before := testutil.ToFloat64(prioritizedRequests.WithLabelValues("resync-test", "low", ReasonResync))
+
+	// Act
+	priority := policy.ResyncPriority("resync-test")
+
+	// Assert
+	require.NotNil(t, priority)
+	assert.Equal(t, handler.LowPriority, *priority)
+	assert.Equal(t, before+1, testutil.ToFloat64(prioritizedRequests.WithLabelValues("resync-test", "low", ReasonResync)))
+}
@@ -0,0 +1,69 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/conditions"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/protection"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/util"
+	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/platform-mesh/golang-commons/sentry"
+)
+
+const (
+	EventReasonDeletionBlocked = "DeletionBlocked"
+
+	reasonDeletionProtected = "DeletionProtected"
+	eventActionFinalize     = "Finalize"
+
+	// DefaultDeletionBlockedRequeueAfter is the delay after which a deletion blocked by deletion protection is
+	// checked again. Removing the annotation does not change the generation and might be filtered by predicates.
+	DefaultDeletionBlockedRequeueAfter = 5 * time.Minute
+)
+
+// blockDeletion skips the finalization of a deletion protected instance in deletion. The finalizers are kept
+// and the DeletionBlocked condition is set until the deletion protection annotation is removed. The instance is
+// requeued, since the removal of the annotation might not trigger a reconcile.
+func blockDeletion(ctx context.Context, cl client.Client, originalCopy runtime.Object, instance runtimeobject.RuntimeObject, l api.Lifecycle, log *logger.Logger, generationChanged bool, sentryTags sentry.Tags) (ctrl.Result, error) {
+	log.Warn().Msg("deletion is blocked by deletion protection, skipping finalization")
+	recordEvent(l, instance, corev1.EventTypeWarning, EventReasonDeletionBlocked, eventActionFinalize, "Finalization is blocked by the %s annotation", protection.DeletionProtectionAnnotation)
+
+	if l.ConditionsManager() != nil {
+		conditionsObj := util.MustToInterface[api.RuntimeObjectConditions](instance, log)
+		condArr := conditionsObj.GetConditions()
+		meta.SetStatusCondition(&condArr, v1.Condition{
+			Type:               conditions.ConditionDeletionBlocked,
+			Status:             v1.ConditionTrue,
+			Reason:             reasonDeletionProtected,
+			Message:            fmt.Sprintf("The deletion is blocked until the %s annotation is removed", protection.DeletionProtectionAnnotation),
+			ObservedGeneration: instance.GetGeneration(),
+		})
+		l.ConditionsManager().SetInstanceConditionReady(&condArr, instance.GetGeneration(), v1.ConditionFalse)
+		conditionsObj.SetConditions(condArr)
+	}
+
+	if !l.Config().ReadOnly {
+		if err := updateStatus(ctx, cl, originalCopy, instance, log, generationChanged, sentryTags); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: deletionBlockedRequeueAfter(l)}, nil
+}
+
+func deletionBlockedRequeueAfter(l api.Lifecycle) time.Duration {
+	if p, ok := l.(api.DeletionProtectingLifecycle); ok && p.DeletionBlockedRequeueAfter() > 0 {
+		return p.DeletionBlockedRequeueAfter()
+	}
+	return DefaultDeletionBlockedRequeueAfter
+}
@@ -0,0 +1,18 @@
+package protection
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeletionProtectionAnnotation protects an object against deletion. Deletes are rejected by the deletion protection
+// webhook and the lifecycle refuses to finalize objects carrying the annotation until it is removed.
+const DeletionProtectionAnnotation = "platform-mesh.io/deletion-protection"
+
+// IsDeletionProtected returns whether the deletion protection annotation is set on the object
+// and not explicitly disabled with the value "false"
+func IsDeletionProtected(obj metav1.Object) bool {
+	val, ok := obj.GetAnnotations()[DeletionProtectionAnnotation]
+	return ok && !strings.EqualFold(val, "false")
+}
@@ -0,0 +1,27 @@
+package protection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsDeletionProtected(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    bool
+	}{
+		{name: "without annotation", annotations: nil, expected: false},
+		{name: "with annotation", annotations: map[string]string{DeletionProtectionAnnotation: "true"}, expected: true},
+		{name: "with empty annotation", annotations: map[string]string{DeletionProtectionAnnotation: ""}, expected: true},
+		{name: "with disabled annotation", annotations: map[string]string{DeletionProtectionAnnotation: "False"}, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Annotations: tt.annotations}
+			assert.Equal(t, tt.expected, IsDeletionProtected(obj))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/conditions"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/protection"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func TestDeletionProtection(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New()
+
+	t.Run("blocks finalization until the annotation is removed", func(t *testing.T) {
+		// Arrange
+		now := metav1.NewTime(time.Now())
+		instance := &pmtesting.ImplementConditions{TestApiObject: pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{
+			Name:              nName.Name,
+			Namespace:         nName.Namespace,
+			DeletionTimestamp: &now,
+			Finalizers:        []string{pmtesting.SubroutineFinalizer},
+			Annotations:       map[string]string{protection.DeletionProtectionAnnotation: "true"},
+		}}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		recorder := events.NewFakeRecorder(10)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.FinalizerSubroutine{}}}).
+			WithEventRecorder(recorder)
+		mgr.WithConditionManagement()
+
+		// Act
+		result, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, DefaultDeletionBlockedRequeueAfter, result.RequeueAfter)
+		assert.Equal(t, []string{pmtesting.SubroutineFinalizer}, instance.Finalizers)
+		blocked := meta.FindStatusCondition(instance.Status.Conditions, conditions.ConditionDeletionBlocked)
+		require.NotNil(t, blocked)
+		assert.Equal(t, metav1.ConditionTrue, blocked.Status)
+		require.Len(t, recorder.Events, 1)
+		assert.Equal(t, "Warning DeletionBlocked Finalization is blocked by the platform-mesh.io/deletion-protection annotation", <-recorder.Events)
+
+		persisted := &pmtesting.ImplementConditions{}
+		require.NoError(t, fakeClient.Get(ctx, nName, persisted))
+		assert.NotNil(t, meta.FindStatusCondition(persisted.Status.Conditions, conditions.ConditionDeletionBlocked))
+
+		// Act
+		persisted.Annotations = nil
+		require.NoError(t, fakeClient.Update(ctx, persisted))
+		_, err = Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Empty(t, instance.Finalizers)
+	})
+
+	t.Run("requeues blocked deletions after the configured delay", func(t *testing.T) {
+		// Arrange
+		now := metav1.NewTime(time.Now())
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{
+			Name:              nName.Name,
+			Namespace:         nName.Namespace,
+			DeletionTimestamp: &now,
+			Finalizers:        []string{pmtesting.SubroutineFinalizer},
+			Annotations:       map[string]string{protection.DeletionProtectionAnnotation: "true"},
+		}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.FinalizerSubroutine{}}}).
+			WithDeletionBlockedRequeueAfter(time.Minute)
+
+		// Act
+		result, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, result.RequeueAfter)
+		assert.Equal(t, []string{pmtesting.SubroutineFinalizer}, instance.Finalizers)
+	})
+
+	t.Run("does not block instances without deletion timestamp", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{
+			Name:        nName.Name,
+			Namespace:   nName.Namespace,
+			Annotations: map[string]string{protection.DeletionProtectionAnnotation: "true"},
+		}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{pmtesting.FinalizerSubroutine{}}}
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{pmtesting.SubroutineFinalizer}, instance.Finalizers)
+		assert.Equal(t, "other string", instance.Status.Some)
+	})
+}
@@ -3,6 +3,8 @@ package ratelimiter
 import (
 	"fmt"
 	"time"
+
+	"k8s.io/utils/clock"
 )
 
 type Config struct {
@@ -10,6 +12,8 @@ type Config struct {
 	StaticWindow              time.Duration
 	ExponentialInitialBackoff time.Duration
 	ExponentialMaxBackoff     time.Duration
+	// Clock is used to measure the static window, defaults to the real clock
+	Clock clock.Clock
 }
 
 var defaultConfig = Config{
@@ -61,6 +65,12 @@ func WithExponentialMaxBackoff(d time.Duration) Option {
 	}
 }
 
+func WithClock(clk clock.Clock) Option {
+	return func(c *Config) {
+		c.Clock = clk
+	}
+}
+
 func NewConfig(options ...Option) Config {
 	cfg := defaultConfig
 
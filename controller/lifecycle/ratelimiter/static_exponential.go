@@ -23,6 +23,10 @@ func NewStaticThenExponentialRateLimiter[T comparable](cfg Config) (*StaticThenE
 	if err := cfg.validate(); err != nil {
 		return nil, err
 	}
+	var c clock.Clock = clock.RealClock{}
+	if cfg.Clock != nil {
+		c = cfg.Clock
+	}
 	return &StaticThenExponentialRateLimiter[T]{
 		staticDelay:  cfg.StaticRequeueDelay,
 		staticWindow: cfg.StaticWindow,
@@ -31,7 +35,7 @@ func NewStaticThenExponentialRateLimiter[T comparable](cfg Config) (*StaticThenE
 			cfg.ExponentialMaxBackoff,
 		),
 		staticAttempts: make(map[T]time.Time),
-		clock:          clock.RealClock{},
+		clock:          c,
 	}, nil
 }
 
@@ -33,6 +33,18 @@ func TestStaticThenExponentialRateLimiter_Forget(t *testing.T) {
 	require.Equal(t, cfg.StaticRequeueDelay, delay)
 }
 
+func TestStaticThenExponentialRateLimiter_WithClock(t *testing.T) {
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	cfg := NewConfig(WithClock(fakeClock))
+	limiter, err := NewStaticThenExponentialRateLimiter[reconcile.Request](cfg)
+	require.NoError(t, err)
+
+	item := reconcile.Request{NamespacedName: types.NamespacedName{Name: "name", Namespace: "namespace"}}
+	require.Equal(t, cfg.StaticRequeueDelay, limiter.When(item))
+	fakeClock.Step(cfg.StaticWindow + time.Second)
+	require.Equal(t, cfg.ExponentialInitialBackoff, limiter.When(item))
+}
+
 func TestStaticThenExponentialRateLimiter_InvalidConfig(t *testing.T) {
 	t.Run("negative static requeue delay", func(t *testing.T) {
 		cfg := Config{
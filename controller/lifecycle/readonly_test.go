@@ -0,0 +1,150 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pmclient "github.com/platform-mesh/golang-commons/controller/client"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+type writingSubroutine struct {
+	client client.Client
+	err    *error
+}
+
+func (s writingSubroutine) Process(ctx context.Context, instance runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	original := instance.DeepCopyObject().(client.Object)
+	instance.(*pmtesting.TestApiObject).Status.Some = "written"
+	*s.err = pmclient.LoadClientFromContext(ctx, s.client).Status().Patch(ctx, instance, client.MergeFrom(original))
+	if *s.err != nil {
+		return ctrl.Result{}, errors.NewOperatorError(*s.err, false, false)
+	}
+	return ctrl.Result{}, nil
+}
+
+func (s writingSubroutine) Finalize(context.Context, runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	return ctrl.Result{}, nil
+}
+
+func (s writingSubroutine) GetName() string { return "writingSubroutine" }
+
+func (s writingSubroutine) Finalizers(runtimeobject.RuntimeObject) []string { return nil }
+
+// capturingInstrumentation records the client of the lifecycle it wraps
+type capturingInstrumentation struct {
+	wrapped client.Client
+}
+
+func (c *capturingInstrumentation) Wrap(cl client.Client, _ string) client.Client {
+	c.wrapped = cl
+	return cl
+}
+
+func TestReadOnlySubroutineClient(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New()
+
+	t.Run("rejects writes of subroutines in read-only mode", func(t *testing.T) {
+		// Arrange
+		var writeErr error
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{writingSubroutine{client: fakeClient, err: &writeErr}}}).
+			WithReadOnly(false)
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, pmclient.IsReadOnlyViolation(writeErr))
+		assert.Contains(t, writeErr.Error(), `subroutine "writingSubroutine" attempted to patch/status`)
+		persisted := &pmtesting.TestApiObject{}
+		require.NoError(t, fakeClient.Get(ctx, nName, persisted))
+		assert.Empty(t, persisted.Status.Some)
+	})
+
+	t.Run("sends writes of subroutines as dry run", func(t *testing.T) {
+		// Arrange
+		var writeErr error
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{writingSubroutine{client: fakeClient, err: &writeErr}}}).
+			WithReadOnly(true)
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		assert.NoError(t, writeErr)
+		persisted := &pmtesting.TestApiObject{}
+		require.NoError(t, fakeClient.Get(ctx, nName, persisted))
+		assert.Empty(t, persisted.Status.Some)
+	})
+
+	t.Run("keeps terminators in read-only mode", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{
+			ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace, DeletionTimestamp: &metav1.Time{Time: time.Now()}, Finalizers: []string{"test-keeping-finalizer"}},
+			Status:     pmtesting.TestStatus{Terminators: []string{"root:terminator"}},
+		}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger}).WithReadOnly(false).WithTerminator("root:terminator")
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		persisted := &pmtesting.TestApiObject{}
+		require.NoError(t, fakeClient.Get(ctx, nName, persisted))
+		assert.Equal(t, []string{"root:terminator"}, persisted.Status.Terminators)
+	})
+
+	t.Run("guards the client of the lifecycle in read-only mode", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		instrumentation := &capturingInstrumentation{}
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{writingSubroutine{client: fakeClient, err: new(error)}}}).
+			WithReadOnly(false).
+			WithClientInstrumentation(instrumentation)
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotNil(t, instrumentation.wrapped)
+		writeErr := instrumentation.wrapped.Delete(ctx, instance)
+		assert.True(t, pmclient.IsReadOnlyViolation(writeErr))
+		assert.Contains(t, writeErr.Error(), `subroutine "lifecycle" attempted to delete`)
+	})
+
+	t.Run("allows writes outside read-only mode", func(t *testing.T) {
+		var writeErr error
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := &pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{writingSubroutine{client: fakeClient, err: &writeErr}}}
+
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		require.NoError(t, err)
+		assert.NoError(t, writeErr)
+	})
+}
@@ -0,0 +1,112 @@
+package report
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+)
+
+// DefaultMinInterval is the default minimal interval between two recorded runs of a subroutine with an unchanged outcome
+const DefaultMinInterval = 5 * time.Minute
+
+type ReportManager struct {
+	minInterval time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	pending map[pendingKey]pendingRuns
+}
+
+// pendingKey identifies the report of a subroutine of an object
+type pendingKey struct {
+	object     string
+	subroutine string
+}
+
+// pendingRuns are the throttled runs not yet counted in the report
+type pendingRuns struct {
+	attempts int64
+	failures int64
+}
+
+// NewReportManager creates a ReportManager recording subroutine runs. To avoid status write storms, runs with the
+// same result and generation as the recorded report are only recorded again after minInterval has passed. Throttled
+// runs are counted in memory and added to Attempts and ConsecutiveFailures with the next recorded run.
+func NewReportManager(minInterval time.Duration) *ReportManager {
+	if minInterval <= 0 {
+		minInterval = DefaultMinInterval
+	}
+	return &ReportManager{minInterval: minInterval, now: time.Now, pending: map[pendingKey]pendingRuns{}}
+}
+
+// RecordSubroutineRun updates the report of the subroutine of the object with the outcome of a run and returns whether
+// the reports changed
+func (r *ReportManager) RecordSubroutineRun(reports *[]api.SubroutineReport, object string, observedGeneration int64, subroutine subroutine.Subroutine, start time.Time, subroutineResult ctrl.Result, subroutineErr error) bool {
+	now := r.now()
+	result := resultOf(subroutineResult, subroutineErr)
+	key := pendingKey{object: object, subroutine: subroutine.GetName()}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := -1
+	for i := range *reports {
+		if (*reports)[i].Name == subroutine.GetName() {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		*reports = append(*reports, api.SubroutineReport{Name: subroutine.GetName()})
+		idx = len(*reports) - 1
+	} else if r.throttled((*reports)[idx], result, observedGeneration, now) {
+		pending := r.pending[key]
+		pending.attempts++
+		if result == api.SubroutineResultError {
+			pending.failures++
+		}
+		r.pending[key] = pending
+		return false
+	}
+
+	pending := r.pending[key]
+	delete(r.pending, key)
+	report := &(*reports)[idx]
+	report.LastRunTime = metav1.NewTime(now)
+	report.Duration = metav1.Duration{Duration: now.Sub(start)}
+	report.Result = result
+	report.Attempts += pending.attempts + 1
+	report.ConsecutiveFailures += pending.failures
+	report.ObservedGeneration = observedGeneration
+	switch result {
+	case api.SubroutineResultSuccess:
+		report.ConsecutiveFailures = 0
+		lastSuccess := metav1.NewTime(now)
+		report.LastSuccessTime = &lastSuccess
+	case api.SubroutineResultError:
+		report.ConsecutiveFailures++
+	}
+	return true
+}
+
+func (r *ReportManager) throttled(existing api.SubroutineReport, result api.SubroutineResult, observedGeneration int64, now time.Time) bool {
+	if existing.Result != result || existing.ObservedGeneration != observedGeneration {
+		return false
+	}
+	return now.Sub(existing.LastRunTime.Time) < r.minInterval
+}
+
+func resultOf(subroutineResult ctrl.Result, subroutineErr error) api.SubroutineResult {
+	if subroutineErr != nil {
+		return api.SubroutineResultError
+	}
+	if subroutineResult.RequeueAfter > 0 {
+		return api.SubroutineResultRequeue
+	}
+	return api.SubroutineResultSuccess
+}
@@ -0,0 +1,129 @@
+package report
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+)
+
+func TestNewReportManager(t *testing.T) {
+	assert.Equal(t, DefaultMinInterval, NewReportManager(0).minInterval)
+	assert.Equal(t, time.Minute, NewReportManager(time.Minute).minInterval)
+}
+
+func TestRecordSubroutineRun(t *testing.T) {
+	sub := pmtesting.ChangeStatusSubroutine{}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rm := NewReportManager(time.Minute)
+	rm.now = func() time.Time { return now }
+
+	var reports []api.SubroutineReport
+
+	t.Run("records a new report", func(t *testing.T) {
+		changed := rm.RecordSubroutineRun(&reports, "bar/foo", 1, sub, now.Add(-2*time.Second), ctrl.Result{}, nil)
+
+		assert.True(t, changed)
+		require.Len(t, reports, 1)
+		assert.Equal(t, sub.GetName(), reports[0].Name)
+		assert.Equal(t, api.SubroutineResultSuccess, reports[0].Result)
+		assert.Equal(t, 2*time.Second, reports[0].Duration.Duration)
+		assert.Equal(t, int64(1), reports[0].Attempts)
+		assert.Equal(t, int64(1), reports[0].ObservedGeneration)
+		require.NotNil(t, reports[0].LastSuccessTime)
+		assert.True(t, now.Equal(reports[0].LastSuccessTime.Time))
+	})
+
+	t.Run("throttles unchanged outcomes", func(t *testing.T) {
+		now = now.Add(30 * time.Second)
+
+		changed := rm.RecordSubroutineRun(&reports, "bar/foo", 1, sub, now, ctrl.Result{}, nil)
+
+		assert.False(t, changed)
+		assert.Equal(t, int64(1), reports[0].Attempts)
+	})
+
+	t.Run("records unchanged outcomes after the min interval including throttled runs", func(t *testing.T) {
+		now = now.Add(time.Minute)
+
+		changed := rm.RecordSubroutineRun(&reports, "bar/foo", 1, sub, now, ctrl.Result{}, nil)
+
+		assert.True(t, changed)
+		assert.Equal(t, int64(3), reports[0].Attempts)
+		assert.Empty(t, rm.pending)
+	})
+
+	t.Run("records errors and counts consecutive failures", func(t *testing.T) {
+		lastSuccess := reports[0].LastSuccessTime.DeepCopy()
+		now = now.Add(time.Second)
+
+		assert.True(t, rm.RecordSubroutineRun(&reports, "bar/foo", 1, sub, now, ctrl.Result{}, errors.New("failed")))
+		now = now.Add(2 * time.Minute)
+		assert.True(t, rm.RecordSubroutineRun(&reports, "bar/foo", 1, sub, now, ctrl.Result{}, errors.New("failed")))
+
+		assert.Equal(t, api.SubroutineResultError, reports[0].Result)
+		assert.Equal(t, int64(2), reports[0].ConsecutiveFailures)
+		assert.Equal(t, int64(5), reports[0].Attempts)
+		assert.Equal(t, lastSuccess, reports[0].LastSuccessTime)
+	})
+
+	t.Run("records generation changes immediately", func(t *testing.T) {
+		now = now.Add(time.Second)
+
+		changed := rm.RecordSubroutineRun(&reports, "bar/foo", 2, sub, now, ctrl.Result{}, errors.New("failed"))
+
+		assert.True(t, changed)
+		assert.Equal(t, int64(2), reports[0].ObservedGeneration)
+		assert.Equal(t, int64(3), reports[0].ConsecutiveFailures)
+	})
+
+	t.Run("records requeues without resetting failures", func(t *testing.T) {
+		now = now.Add(time.Second)
+
+		changed := rm.RecordSubroutineRun(&reports, "bar/foo", 2, sub, now, ctrl.Result{RequeueAfter: time.Second}, nil)
+
+		assert.True(t, changed)
+		assert.Equal(t, api.SubroutineResultRequeue, reports[0].Result)
+		assert.Equal(t, int64(3), reports[0].ConsecutiveFailures)
+	})
+
+	t.Run("resets failures on success", func(t *testing.T) {
+		now = now.Add(time.Second)
+
+		changed := rm.RecordSubroutineRun(&reports, "bar/foo", 2, sub, now, ctrl.Result{}, nil)
+
+		assert.True(t, changed)
+		assert.Equal(t, int64(0), reports[0].ConsecutiveFailures)
+		assert.True(t, now.Equal(reports[0].LastSuccessTime.Time))
+	})
+}
+
+func TestRecordSubroutineRunCountsThrottledFailures(t *testing.T) {
+	sub := pmtesting.ChangeStatusSubroutine{}
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rm := NewReportManager(time.Minute)
+	rm.now = func() time.Time { return now }
+	var reports, otherReports []api.SubroutineReport
+
+	for range 5 {
+		rm.RecordSubroutineRun(&reports, "bar/foo", 1, sub, now, ctrl.Result{}, errors.New("failed"))
+		now = now.Add(5 * time.Second)
+	}
+	rm.RecordSubroutineRun(&otherReports, "bar/other", 1, sub, now, ctrl.Result{}, errors.New("failed"))
+	throttled := reports[0]
+	now = now.Add(time.Minute)
+	changed := rm.RecordSubroutineRun(&reports, "bar/foo", 1, sub, now, ctrl.Result{RequeueAfter: time.Second}, nil)
+
+	assert.Equal(t, int64(1), throttled.ConsecutiveFailures)
+	assert.True(t, changed)
+	assert.Equal(t, api.SubroutineResultRequeue, reports[0].Result)
+	assert.Equal(t, int64(6), reports[0].Attempts)
+	assert.Equal(t, int64(5), reports[0].ConsecutiveFailures)
+	assert.Equal(t, int64(1), otherReports[0].ConsecutiveFailures)
+}
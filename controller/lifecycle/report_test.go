@@ -0,0 +1,58 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/report"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func TestSubroutineReports(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New()
+
+	t.Run("persists a report per subroutine", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.ImplementSubroutineReports{TestApiObject: pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace, Generation: 3}}}
+		fakeClient := pmtesting.CreateFakeClient(t, instance)
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{
+			pmtesting.ChangeStatusSubroutine{},
+			pmtesting.FailureScenarioSubroutine{Retry: true},
+		}}).WithReportManager(report.NewReportManager(time.Minute))
+
+		// Act
+		_, err := Reconcile(ctx, nName, instance, fakeClient, mgr)
+
+		// Assert
+		require.Error(t, err)
+		persisted := &pmtesting.ImplementSubroutineReports{}
+		require.NoError(t, fakeClient.Get(ctx, nName, persisted))
+		reports := persisted.GetSubroutineReports()
+		require.Len(t, reports, 2)
+		assert.Equal(t, "changeStatus", reports[0].Name)
+		assert.Equal(t, api.SubroutineResultSuccess, reports[0].Result)
+		assert.Equal(t, int64(3), reports[0].ObservedGeneration)
+		assert.Equal(t, "FailureScenarioSubroutine", reports[1].Name)
+		assert.Equal(t, api.SubroutineResultError, reports[1].Result)
+		assert.Equal(t, int64(1), reports[1].ConsecutiveFailures)
+	})
+
+	t.Run("fails validation if the instance does not support reports", func(t *testing.T) {
+		mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger}).WithReportManager(report.NewReportManager(time.Minute))
+
+		err := ValidateInterfaces(&pmtesting.TestApiObject{}, log.Logger, mgr)
+
+		assert.Error(t, err)
+	})
+}
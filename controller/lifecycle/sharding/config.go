@@ -0,0 +1,63 @@
+package sharding
+
+import (
+	"fmt"
+	"time"
+)
+
+type Config struct {
+	// Identity identifies this replica, defaults to the hostname which equals the pod name
+	Identity string
+	// LeaseDuration is the duration after which a replica not renewing its lease is considered gone
+	LeaseDuration time.Duration
+	// RenewInterval is the interval in which the lease is renewed and the replicas are refreshed
+	RenewInterval time.Duration
+}
+
+var defaultConfig = Config{
+	LeaseDuration: 30 * time.Second,
+	RenewInterval: 10 * time.Second,
+}
+
+func (c Config) validate() error {
+	if c.Identity == "" {
+		return fmt.Errorf("the identity should not be empty")
+	}
+	if c.LeaseDuration <= 0 {
+		return fmt.Errorf("the lease duration should be positive")
+	}
+	if c.RenewInterval <= 0 || c.RenewInterval >= c.LeaseDuration {
+		return fmt.Errorf("the renew interval should be positive and less than the lease duration")
+	}
+	return nil
+}
+
+type Option func(*Config)
+
+func WithIdentity(identity string) Option {
+	return func(c *Config) {
+		c.Identity = identity
+	}
+}
+
+func WithLeaseDuration(d time.Duration) Option {
+	return func(c *Config) {
+		c.LeaseDuration = d
+	}
+}
+
+func WithRenewInterval(d time.Duration) Option {
+	return func(c *Config) {
+		c.RenewInterval = d
+	}
+}
+
+func NewConfig(options ...Option) Config {
+	cfg := defaultConfig
+
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	return cfg
+}
@@ -0,0 +1,85 @@
+package sharding
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
+)
+
+// NewClusterQueue returns a NewQueue function for multicluster controllers, constructing the same queue as
+// controller-runtime does by default but dropping the requests of clusters not owned by this replica. Predicates of
+// multicluster controllers don't know the cluster of an event, so the requests are filtered before they are queued.
+func (s *Sharder) NewClusterQueue(usePriorityQueue bool) func(controllerName string, rateLimiter workqueue.TypedRateLimiter[mcreconcile.Request]) workqueue.TypedRateLimitingInterface[mcreconcile.Request] {
+	return func(controllerName string, rateLimiter workqueue.TypedRateLimiter[mcreconcile.Request]) workqueue.TypedRateLimitingInterface[mcreconcile.Request] {
+		if usePriorityQueue {
+			return &clusterPriorityQueue{
+				PriorityQueue: priorityqueue.New(controllerName, func(o *priorityqueue.Opts[mcreconcile.Request]) {
+					o.Log = s.log.Logr().WithValues("controller", controllerName)
+					o.RateLimiter = rateLimiter
+				}),
+				sharder: s,
+			}
+		}
+		return &clusterQueue{
+			TypedRateLimitingInterface: workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[mcreconcile.Request]{
+				Name: controllerName,
+			}),
+			sharder: s,
+		}
+	}
+}
+
+type clusterQueue struct {
+	workqueue.TypedRateLimitingInterface[mcreconcile.Request]
+	sharder *Sharder
+}
+
+func (q *clusterQueue) Add(item mcreconcile.Request) {
+	if q.sharder.Owns(item.ClusterName) {
+		q.TypedRateLimitingInterface.Add(item)
+	}
+}
+
+func (q *clusterQueue) AddAfter(item mcreconcile.Request, duration time.Duration) {
+	if q.sharder.Owns(item.ClusterName) {
+		q.TypedRateLimitingInterface.AddAfter(item, duration)
+	}
+}
+
+func (q *clusterQueue) AddRateLimited(item mcreconcile.Request) {
+	if q.sharder.Owns(item.ClusterName) {
+		q.TypedRateLimitingInterface.AddRateLimited(item)
+	}
+}
+
+// clusterPriorityQueue keeps implementing priorityqueue.PriorityQueue, so that handlers can still set priorities
+type clusterPriorityQueue struct {
+	priorityqueue.PriorityQueue[mcreconcile.Request]
+	sharder *Sharder
+}
+
+func (q *clusterPriorityQueue) Add(item mcreconcile.Request) {
+	q.AddWithOpts(priorityqueue.AddOpts{}, item)
+}
+
+func (q *clusterPriorityQueue) AddAfter(item mcreconcile.Request, duration time.Duration) {
+	q.AddWithOpts(priorityqueue.AddOpts{After: duration}, item)
+}
+
+func (q *clusterPriorityQueue) AddRateLimited(item mcreconcile.Request) {
+	q.AddWithOpts(priorityqueue.AddOpts{RateLimited: true}, item)
+}
+
+func (q *clusterPriorityQueue) AddWithOpts(o priorityqueue.AddOpts, items ...mcreconcile.Request) {
+	owned := make([]mcreconcile.Request, 0, len(items))
+	for _, item := range items {
+		if q.sharder.Owns(item.ClusterName) {
+			owned = append(owned, item)
+		}
+	}
+	if len(owned) > 0 {
+		q.PriorityQueue.AddWithOpts(o, owned...)
+	}
+}
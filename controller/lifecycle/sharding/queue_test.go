@@ -0,0 +1,79 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/priorityqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
+)
+
+func TestSharder_NewClusterQueue(t *testing.T) {
+	now := time.Now()
+	s := newTestSharder(t, fake.NewClientBuilder().Build(), "a", &now)
+	require.NoError(t, s.Sync(context.Background()))
+	s.members = []string{"a", "b"}
+
+	var owned, foreign string
+	for i := range 20 {
+		name := fmt.Sprintf("cluster-%d", i)
+		if s.Owns(name) {
+			owned = name
+		} else {
+			foreign = name
+		}
+	}
+	require.NotEmpty(t, owned)
+	require.NotEmpty(t, foreign)
+	request := func(cluster string) mcreconcile.Request {
+		return mcreconcile.Request{ClusterName: cluster, Request: reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo", Namespace: "bar"}}}
+	}
+
+	for _, usePriorityQueue := range []bool{true, false} {
+		t.Run(map[bool]string{true: "priority queue", false: "rate limiting queue"}[usePriorityQueue], func(t *testing.T) {
+			// Arrange
+			q := s.NewClusterQueue(usePriorityQueue)("cluster-queue-test", workqueue.DefaultTypedControllerRateLimiter[mcreconcile.Request]())
+			defer q.ShutDown()
+
+			// Act
+			q.Add(request(foreign))
+			q.AddAfter(request(foreign), time.Millisecond)
+			q.AddRateLimited(request(foreign))
+			q.Add(request(owned))
+
+			// Assert
+			item, _ := q.Get()
+			assert.Equal(t, request(owned), item)
+			q.Done(item)
+			assert.Never(t, func() bool { return q.Len() > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+
+			_, isPriorityQueue := q.(priorityqueue.PriorityQueue[mcreconcile.Request])
+			assert.Equal(t, usePriorityQueue, isPriorityQueue)
+		})
+	}
+
+	t.Run("drops prioritized requests of foreign clusters", func(t *testing.T) {
+		// Arrange
+		q := s.NewClusterQueue(true)("cluster-queue-test", workqueue.DefaultTypedControllerRateLimiter[mcreconcile.Request]())
+		defer q.ShutDown()
+		pq := q.(priorityqueue.PriorityQueue[mcreconcile.Request])
+
+		// Act
+		pq.AddWithOpts(priorityqueue.AddOpts{Priority: ptr.To(10)}, request(foreign), request(owned))
+
+		// Assert
+		item, priority, _ := pq.GetWithPriority()
+		assert.Equal(t, request(owned), item)
+		assert.Equal(t, 10, priority)
+		assert.Equal(t, 0, pq.Len())
+	})
+}
@@ -0,0 +1,260 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/cluster"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/platform-mesh/golang-commons/logger"
+)
+
+// ShardGroupLabel is set on the leases of all replicas sharing the work of a shard group
+const ShardGroupLabel = "sharding.platform-mesh.io/group"
+
+// Sharder partitions objects, or clusters in multicluster mode, among the replicas of a shard group.
+// Each replica maintains a Lease in the configured namespace, and keys are assigned to the live replicas
+// using rendezvous hashing, so that only the keys of joining or leaving replicas move on a rebalance.
+// Sharder implements manager.Runnable and has to be added to the manager.
+type Sharder struct {
+	cfg       Config
+	client    client.Client
+	namespace string
+	group     string
+	log       *logger.Logger
+	now       func() time.Time
+
+	mu        sync.RWMutex
+	members   []string
+	clusters  map[string]cluster.Cluster
+	listeners []func(ctx context.Context)
+}
+
+// NewSharder creates a Sharder for the shard group. The client is used to maintain the leases in the namespace
+// and should not be backed by a cache, to avoid watching leases in all namespaces.
+func NewSharder(cl client.Client, namespace, group string, log *logger.Logger, opts ...Option) (*Sharder, error) {
+	cfg := NewConfig(opts...)
+	if cfg.Identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine identity: %w", err)
+		}
+		cfg.Identity = hostname
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if namespace == "" || group == "" {
+		return nil, fmt.Errorf("namespace and group should not be empty")
+	}
+	return &Sharder{
+		cfg:       cfg,
+		client:    cl,
+		namespace: namespace,
+		group:     group,
+		log:       log.ComponentLogger("sharder").MustChildLoggerWithAttributes("group", group, "identity", cfg.Identity),
+		now:       time.Now,
+		clusters:  map[string]cluster.Cluster{},
+	}, nil
+}
+
+// ShardKey returns the key used to assign an object to a shard
+func ShardKey(obj client.Object) string {
+	return client.ObjectKeyFromObject(obj).String()
+}
+
+// Owns returns whether the key is assigned to this replica. Before the replicas are known, no key is owned.
+func (s *Sharder) Owns(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var owner string
+	var highest uint64
+	for _, member := range s.members {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(member + "/" + key))
+		if score := h.Sum64(); owner == "" || score > highest {
+			owner, highest = member, score
+		}
+	}
+	return owner != "" && owner == s.cfg.Identity
+}
+
+// Members returns the identities of all live replicas of the shard group
+func (s *Sharder) Members() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.members)
+}
+
+// Predicate returns a predicate filtering events of objects not owned by this replica
+func (s *Sharder) Predicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return s.Owns(ShardKey(e.Object)) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return s.Owns(ShardKey(e.ObjectNew)) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return s.Owns(ShardKey(e.Object)) },
+		GenericFunc: func(e event.GenericEvent) bool { return s.Owns(ShardKey(e.Object)) },
+	}
+}
+
+// OnRebalance registers a function called in its own goroutine whenever the replicas of the shard group changed
+func (s *Sharder) OnRebalance(f func(ctx context.Context)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, f)
+}
+
+// Engage tracks the clusters engaged by a multicluster manager, which are resynced on a rebalance
+func (s *Sharder) Engage(ctx context.Context, name string, cl cluster.Cluster) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clusters[name] = cl
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.clusters[name] == cl {
+			delete(s.clusters, name)
+		}
+	}()
+	return nil
+}
+
+// Clusters returns the engaged clusters owned by this replica
+func (s *Sharder) Clusters() map[string]cluster.Cluster {
+	s.mu.RLock()
+	clusters := make(map[string]cluster.Cluster, len(s.clusters))
+	for name, cl := range s.clusters {
+		clusters[name] = cl
+	}
+	s.mu.RUnlock()
+
+	for name := range clusters {
+		if !s.Owns(name) {
+			delete(clusters, name)
+		}
+	}
+	return clusters
+}
+
+// Start renews the lease of this replica and refreshes the replicas of the shard group until the context is done.
+// The lease is released on shutdown to hand over the keys of this replica immediately.
+func (s *Sharder) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.RenewInterval)
+	defer ticker.Stop()
+	defer s.release()
+
+	for {
+		if err := s.Sync(ctx); err != nil {
+			s.log.Error().Err(err).Msg("failed to sync shard group")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns false, since every replica has to maintain its lease
+func (s *Sharder) NeedLeaderElection() bool {
+	return false
+}
+
+// Sync renews the lease of this replica and refreshes the live replicas, notifying the rebalance listeners on changes
+func (s *Sharder) Sync(ctx context.Context) error {
+	if err := s.renew(ctx); err != nil {
+		return err
+	}
+
+	leases := &coordinationv1.LeaseList{}
+	if err := s.client.List(ctx, leases, client.InNamespace(s.namespace), client.MatchingLabels{ShardGroupLabel: s.group}); err != nil {
+		return fmt.Errorf("failed to list leases: %w", err)
+	}
+
+	now := s.now()
+	var members []string
+	for _, lease := range leases.Items {
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if now.Before(expiry) {
+			members = append(members, *lease.Spec.HolderIdentity)
+		}
+	}
+	slices.Sort(members)
+	members = slices.Compact(members)
+
+	s.mu.Lock()
+	changed := !slices.Equal(s.members, members)
+	s.members = members
+	listeners := slices.Clone(s.listeners)
+	s.mu.Unlock()
+
+	if changed {
+		s.log.Info().Strs("members", members).Msg("shard group changed, rebalancing")
+		for _, f := range listeners {
+			go f(ctx)
+		}
+	}
+	return nil
+}
+
+func (s *Sharder) leaseName() string {
+	return fmt.Sprintf("%s-%s", s.group, s.cfg.Identity)
+}
+
+func (s *Sharder) renew(ctx context.Context) error {
+	renewTime := metav1.NewMicroTime(s.now())
+	lease := &coordinationv1.Lease{}
+	err := s.client.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: s.leaseName()}, lease)
+	if kerrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: s.leaseName(), Namespace: s.namespace, Labels: map[string]string{ShardGroupLabel: s.group}},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(s.cfg.Identity),
+				LeaseDurationSeconds: ptr.To(int32(s.cfg.LeaseDuration.Seconds())),
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}
+		if err := s.client.Create(ctx, lease); err != nil {
+			return fmt.Errorf("failed to create lease: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get lease: %w", err)
+	}
+
+	lease.Spec.HolderIdentity = ptr.To(s.cfg.Identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(s.cfg.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &renewTime
+	if err := s.client.Update(ctx, lease); err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+	return nil
+}
+
+func (s *Sharder) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.RenewInterval)
+	defer cancel()
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: s.leaseName(), Namespace: s.namespace}}
+	if err := s.client.Delete(ctx, lease); client.IgnoreNotFound(err) != nil {
+		s.log.Error().Err(err).Msg("failed to release lease")
+	}
+}
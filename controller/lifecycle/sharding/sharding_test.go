@@ -0,0 +1,149 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func newTestSharder(t *testing.T, cl client.Client, identity string, now *time.Time) *Sharder {
+	s, err := NewSharder(cl, "default", "operator", testlogger.New().Logger, WithIdentity(identity))
+	require.NoError(t, err)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestNewSharder(t *testing.T) {
+	log := testlogger.New().Logger
+	cl := fake.NewClientBuilder().Build()
+
+	_, err := NewSharder(cl, "", "operator", log, WithIdentity("a"))
+	assert.Error(t, err)
+	_, err = NewSharder(cl, "default", "operator", log, WithIdentity("a"), WithRenewInterval(time.Minute))
+	assert.Error(t, err)
+
+	s, err := NewSharder(cl, "default", "operator", log)
+	require.NoError(t, err)
+	assert.NotEmpty(t, s.cfg.Identity)
+}
+
+func TestSharder(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cl := fake.NewClientBuilder().Build()
+	a := newTestSharder(t, cl, "a", &now)
+	b := newTestSharder(t, cl, "b", &now)
+
+	keys := make([]string, 100)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("default/object-%d", i)
+	}
+
+	t.Run("owns nothing before the first sync", func(t *testing.T) {
+		assert.False(t, a.Owns(keys[0]))
+	})
+
+	t.Run("creates a lease per replica", func(t *testing.T) {
+		require.NoError(t, a.Sync(ctx))
+		require.NoError(t, b.Sync(ctx))
+		require.NoError(t, a.Sync(ctx))
+
+		leases := &coordinationv1.LeaseList{}
+		require.NoError(t, cl.List(ctx, leases, client.MatchingLabels{ShardGroupLabel: "operator"}))
+		assert.Len(t, leases.Items, 2)
+		assert.Equal(t, []string{"a", "b"}, a.Members())
+		assert.Equal(t, []string{"a", "b"}, b.Members())
+	})
+
+	t.Run("assigns every key to exactly one replica", func(t *testing.T) {
+		ownedByA := 0
+		for _, key := range keys {
+			assert.NotEqual(t, a.Owns(key), b.Owns(key), key)
+			if a.Owns(key) {
+				ownedByA++
+			}
+		}
+		assert.Greater(t, ownedByA, 0)
+		assert.Less(t, ownedByA, len(keys))
+	})
+
+	t.Run("rebalances when a replica is gone", func(t *testing.T) {
+		rebalanced := make(chan []string, 1)
+		a.OnRebalance(func(context.Context) { rebalanced <- a.Members() })
+
+		now = now.Add(time.Minute)
+		require.NoError(t, a.Sync(ctx))
+
+		select {
+		case members := <-rebalanced:
+			assert.Equal(t, []string{"a"}, members)
+		case <-time.After(time.Second):
+			t.Fatal("rebalance listener not called")
+		}
+		for _, key := range keys {
+			assert.True(t, a.Owns(key))
+		}
+	})
+
+	t.Run("releases the lease on shutdown", func(t *testing.T) {
+		runCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		require.NoError(t, b.Start(runCtx))
+
+		lease := &coordinationv1.Lease{}
+		err := cl.Get(ctx, client.ObjectKey{Namespace: "default", Name: "operator-b"}, lease)
+		assert.True(t, kerrors.IsNotFound(err))
+	})
+}
+
+func TestSharder_Predicate(t *testing.T) {
+	now := time.Now()
+	s := newTestSharder(t, fake.NewClientBuilder().Build(), "a", &now)
+	require.NoError(t, s.Sync(context.Background()))
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+	p := s.Predicate()
+	assert.True(t, p.Create(event.CreateEvent{Object: obj}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: obj, ObjectNew: obj}))
+
+	s.members = []string{"b"}
+	assert.False(t, p.Delete(event.DeleteEvent{Object: obj}))
+	assert.False(t, p.Generic(event.GenericEvent{Object: obj}))
+}
+
+func TestSharder_ResyncSource(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Now()
+	cl := fake.NewClientBuilder().WithObjects(
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "baz", Namespace: "bar"}},
+	).Build()
+	s := newTestSharder(t, cl, "a", &now)
+
+	src, err := s.ResyncSource(cl, scheme.Scheme, &corev1.ConfigMap{})
+	require.NoError(t, err)
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+	require.NoError(t, src.Start(ctx, queue))
+
+	require.NoError(t, s.Sync(ctx))
+
+	assert.Eventually(t, func() bool { return queue.Len() == 2 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,98 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
+)
+
+// ResyncSource returns a source enqueuing all objects of the type of object owned by this replica after a rebalance.
+// Events of these objects were dropped by the Predicate while they were owned by another replica.
+func (s *Sharder) ResyncSource(cl client.Reader, scheme *runtime.Scheme, object client.Object) (source.Source, error) {
+	list, err := newListFor(scheme, object)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan event.GenericEvent)
+	s.OnRebalance(func(ctx context.Context) {
+		_ = s.forEachObject(ctx, cl, list, func(obj client.Object) error {
+			if !s.Owns(ShardKey(obj)) {
+				return nil
+			}
+			select {
+			case ch <- event.GenericEvent{Object: obj}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	})
+	return source.Channel(ch, &handler.EnqueueRequestForObject{}), nil
+}
+
+// ClusterResyncSource returns a source enqueuing all objects of the type of object in the engaged clusters owned by
+// this replica after a rebalance. The Sharder has to be added to the multicluster manager to get the clusters engaged.
+func (s *Sharder) ClusterResyncSource(scheme *runtime.Scheme, object client.Object) (source.TypedSource[mcreconcile.Request], error) {
+	list, err := newListFor(scheme, object)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan event.TypedGenericEvent[mcreconcile.Request])
+	s.OnRebalance(func(ctx context.Context) {
+		for name, cl := range s.Clusters() {
+			_ = s.forEachObject(ctx, cl.GetClient(), list, func(obj client.Object) error {
+				req := mcreconcile.Request{ClusterName: name, Request: reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)}}
+				select {
+				case ch <- event.TypedGenericEvent[mcreconcile.Request]{Object: req}:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+		}
+	})
+	return source.TypedChannel(ch, handler.TypedFuncs[mcreconcile.Request, mcreconcile.Request]{
+		GenericFunc: func(_ context.Context, e event.TypedGenericEvent[mcreconcile.Request], q workqueue.TypedRateLimitingInterface[mcreconcile.Request]) {
+			q.Add(e.Object)
+		},
+	}), nil
+}
+
+func (s *Sharder) forEachObject(ctx context.Context, cl client.Reader, list client.ObjectList, f func(obj client.Object) error) error {
+	list = list.DeepCopyObject().(client.ObjectList)
+	if err := cl.List(ctx, list); err != nil {
+		s.log.Error().Err(err).Msg("failed to list objects to resync")
+		return err
+	}
+	return meta.EachListItem(list, func(o runtime.Object) error {
+		return f(o.(client.Object))
+	})
+}
+
+func newListFor(scheme *runtime.Scheme, obj client.Object) (client.ObjectList, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	o, err := scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return nil, err
+	}
+	list, ok := o.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("type %T is not a client.ObjectList", o)
+	}
+	return list, nil
+}
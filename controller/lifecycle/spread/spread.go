@@ -5,6 +5,7 @@ import (
 	"time"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
@@ -16,10 +17,29 @@ import (
 const ReconcileRefreshLabel = "platform-mesh.io/refresh-reconcile"
 
 type Spreader struct {
+	clock clock.PassiveClock
 }
 
 func NewSpreader() *Spreader {
-	return &Spreader{}
+	return &Spreader{clock: clock.RealClock{}}
+}
+
+// WithClock replaces the clock used to calculate and check the next reconcile time, e.g. with a fake clock in tests
+func (s *Spreader) WithClock(c clock.PassiveClock) *Spreader {
+	s.clock = c
+	return s
+}
+
+// Clock returns the clock used to calculate and check the next reconcile time
+func (s *Spreader) Clock() clock.PassiveClock {
+	return s.clock
+}
+
+func (s *Spreader) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
 }
 
 type GenerateNextReconcileTimer interface {
@@ -40,7 +60,7 @@ func getNextReconcileTime(maxReconcileTime time.Duration) time.Duration {
 
 func (s *Spreader) OnNextReconcile(instance runtimeobject.RuntimeObject, log *logger.Logger) (ctrl.Result, error) {
 	instanceStatusObj := util.MustToInterface[api.RuntimeObjectSpreadReconcileStatus](instance, log)
-	requeueAfter := time.Until(instanceStatusObj.GetNextReconcileTime().UTC())
+	requeueAfter := instanceStatusObj.GetNextReconcileTime().UTC().Sub(s.now())
 	log.Debug().Int64("minutes-till-next-execution", int64(requeueAfter.Minutes())).Msg("Completed reconciliation, no processing needed")
 	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
@@ -56,7 +76,7 @@ func (s *Spreader) SetNextReconcileTime(instanceStatusObj api.RuntimeObjectSprea
 	nextReconcileTime := getNextReconcileTime(border)
 
 	log.Debug().Int64("minutes-till-next-execution", int64(nextReconcileTime.Minutes())).Msg("Setting next reconcile time for the instance")
-	instanceStatusObj.SetNextReconcileTime(v1.NewTime(time.Now().Add(nextReconcileTime)))
+	instanceStatusObj.SetNextReconcileTime(v1.NewTime(s.now().Add(nextReconcileTime)))
 }
 
 // UpdateObservedGeneration updates the observed generation of the instance struct
@@ -74,7 +94,7 @@ func (s *Spreader) ReconcileRequired(instance runtimeobject.RuntimeObject, log *
 
 	instanceStatusObj := util.MustToInterface[api.RuntimeObjectSpreadReconcileStatus](instance, log)
 	generationChanged := instance.GetGeneration() != instanceStatusObj.GetObservedGeneration()
-	isAfterNextReconcileTime := v1.Now().UTC().After(instanceStatusObj.GetNextReconcileTime().UTC())
+	isAfterNextReconcileTime := s.now().UTC().After(instanceStatusObj.GetNextReconcileTime().UTC())
 	_, refreshRequested := instance.GetLabels()[ReconcileRefreshLabel]
 
 	return generationChanged || isAfterNextReconcileTime || refreshRequested
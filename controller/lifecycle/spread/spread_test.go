@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
 
 	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
 	"github.com/platform-mesh/golang-commons/logger/testlogger"
@@ -201,3 +202,30 @@ func TestReconcileRequired(t *testing.T) {
 	}
 	assert.False(t, s.ReconcileRequired(apiObject4, tl.Logger), "Should not require reconcile when no condition met")
 }
+
+func TestSpreaderWithClock(t *testing.T) {
+	// Arrange
+	tl := testlogger.New()
+	fakeClock := clocktesting.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewSpreader().WithClock(fakeClock)
+	apiObject := &pmtesting.ImplementingSpreadReconciles{
+		TestApiObject: pmtesting.TestApiObject{ObjectMeta: v1.ObjectMeta{Generation: 1}, Status: pmtesting.TestStatus{ObservedGeneration: 1}},
+	}
+
+	// Act
+	s.SetNextReconcileTime(apiObject, tl.Logger)
+
+	// Assert
+	next := apiObject.GetNextReconcileTime().Time
+	assert.True(t, next.After(fakeClock.Now().Add(12*time.Hour-time.Minute)))
+	assert.False(t, s.ReconcileRequired(apiObject, tl.Logger))
+	result, err := s.OnNextReconcile(apiObject, tl.Logger)
+	assert.NoError(t, err)
+	assert.Equal(t, next.Sub(fakeClock.Now()), result.RequeueAfter)
+
+	// Act
+	fakeClock.SetTime(next.Add(time.Second))
+
+	// Assert
+	assert.True(t, s.ReconcileRequired(apiObject, tl.Logger))
+}
@@ -6,6 +6,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/watch"
 	"github.com/platform-mesh/golang-commons/errors"
 )
 
@@ -36,3 +37,49 @@ type Terminator interface {
 type Initializer interface {
 	Initialize(ctx context.Context, instance runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError)
 }
+
+// Watcher can be implemented by subroutines that read or own secondary
+// resources. The returned watches, including their field indexes, are
+// registered by the SetupWithManagerBuilder of the lifecycle managers so
+// that changes to the secondary resources trigger a reconcile of the primary
+// object.
+type Watcher interface {
+	Watches() []watch.Watch
+}
+
+// InputHasher can be implemented by subroutines to skip processing while
+// their inputs are unchanged. The returned hash should be computed from the
+// relevant spec fields and referenced objects. Use together with
+// LifecycleManager.WithSkipUnchangedSubroutines(), the lifecycle then skips
+// the subroutine as long as hash and generation match the last successful run.
+type InputHasher interface {
+	InputHash(ctx context.Context, instance runtimeobject.RuntimeObject) (string, errors.OperatorError)
+}
+
+// FinalizeCheckpointer has to be implemented by subroutines recording their
+// finalization progress with checkpoint.LoadCheckpointFromContext. Managers
+// with such a subroutine reject instances not implementing
+// api.RuntimeObjectFinalizeCheckpoints when they are set up, as the progress
+// could not be persisted across reconciles.
+type FinalizeCheckpointer interface {
+	CheckpointsFinalization() bool
+}
+
+// FinalizePrioritizer can be implemented by subroutines to change their
+// position in the finalization order, which is the reverse process order by
+// default. Subroutines with a higher priority are finalized first, the
+// default priority is 0.
+type FinalizePrioritizer interface {
+	FinalizePriority() int
+}
+
+// FinalizeDependent can be implemented by subroutines that may only be
+// finalized after other subroutines, e.g. to release a tenant only after all
+// resources of the tenant were deleted. FinalizeAfter returns the names of
+// these subroutines. The subroutine is not finalized while a finalizer of a
+// dependency is still on the instance, e.g. after the dependency requested a
+// requeue. Dependencies take precedence over priorities, cycles are rejected
+// when the manager is set up.
+type FinalizeDependent interface {
+	FinalizeAfter() []string
+}
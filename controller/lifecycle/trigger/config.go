@@ -0,0 +1,77 @@
+package trigger
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	// Address is the address the endpoint listens on, defaults to the loopback interface. Only expose the endpoint on
+	// other interfaces behind the service mesh or with an authorizer verifying tokens, e.g. AllowServiceAccounts.
+	Address string
+	// Path is the path of the endpoint
+	Path string
+	// Authorize authorizes requests once the auth middlewares stored the web token and the SPIFFE ID in the context
+	Authorize Authorizer
+	// EnqueueTimeout is the duration a request waits for the controller to accept the reconcile
+	EnqueueTimeout time.Duration
+}
+
+var defaultConfig = Config{
+	Address:        "127.0.0.1:8091",
+	Path:           "/reconcile",
+	EnqueueTimeout: 5 * time.Second,
+}
+
+func (c Config) validate() error {
+	if c.Address == "" {
+		return fmt.Errorf("the address should not be empty")
+	}
+	if !strings.HasPrefix(c.Path, "/") {
+		return fmt.Errorf("the path should start with /")
+	}
+	if c.Authorize == nil {
+		return fmt.Errorf("an authorizer is required")
+	}
+	if c.EnqueueTimeout <= 0 {
+		return fmt.Errorf("the enqueue timeout should be positive")
+	}
+	return nil
+}
+
+type Option func(*Config)
+
+func WithAddress(address string) Option {
+	return func(c *Config) {
+		c.Address = address
+	}
+}
+
+func WithPath(path string) Option {
+	return func(c *Config) {
+		c.Path = path
+	}
+}
+
+func WithAuthorizer(authorize Authorizer) Option {
+	return func(c *Config) {
+		c.Authorize = authorize
+	}
+}
+
+func WithEnqueueTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.EnqueueTimeout = d
+	}
+}
+
+func NewConfig(options ...Option) Config {
+	cfg := defaultConfig
+
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	return cfg
+}
@@ -0,0 +1,265 @@
+package trigger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
+
+	pmcontext "github.com/platform-mesh/golang-commons/context"
+	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/platform-mesh/golang-commons/middleware"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// ErrUnauthenticated is returned by authorizers for requests without the required identity
+var ErrUnauthenticated = errors.New("the request is not authenticated")
+
+// Authorizer authorizes a trigger request based on the identity stored in the context by the auth middlewares. It
+// returns ErrUnauthenticated for requests without identity and another error for requests which are not allowed.
+type Authorizer func(ctx context.Context) error
+
+// AllowSpiffeIDs allows requests of workloads with one of the SPIFFE IDs, as forwarded by the service mesh. The
+// forwarded header is trusted as is, so the endpoint must only be reachable through the mesh, which sets the header.
+func AllowSpiffeIDs(ids ...string) Authorizer {
+	return func(ctx context.Context) error {
+		spiffe, err := pmcontext.GetSpiffeFromContext(ctx)
+		if err != nil || spiffe == "" {
+			return ErrUnauthenticated
+		}
+		if !slices.Contains(ids, spiffe) {
+			return fmt.Errorf("the SPIFFE ID %s is not allowed to trigger reconciles", spiffe)
+		}
+		return nil
+	}
+}
+
+// AllowTokenSubjects allows requests with a web token of one of the subjects. The token is not verified, so the
+// endpoint must only be reachable through a mesh verifying it, e.g. with an istio RequestAuthentication.
+func AllowTokenSubjects(subjects ...string) Authorizer {
+	return func(ctx context.Context) error {
+		token, err := pmcontext.GetWebTokenFromContext(ctx)
+		if err != nil || token.Subject == "" {
+			return ErrUnauthenticated
+		}
+		if !slices.Contains(subjects, token.Subject) {
+			return fmt.Errorf("the subject %s is not allowed to trigger reconciles", token.Subject)
+		}
+		return nil
+	}
+}
+
+// AllowServiceAccounts allows requests with a bearer token of one of the service accounts. The token is verified with
+// a TokenReview, so the client needs permission to create tokenreviews.authentication.k8s.io.
+func AllowServiceAccounts(cl client.Client, serviceAccounts ...types.NamespacedName) Authorizer {
+	users := make([]string, 0, len(serviceAccounts))
+	for _, sa := range serviceAccounts {
+		users = append(users, fmt.Sprintf("system:serviceaccount:%s:%s", sa.Namespace, sa.Name))
+	}
+	return func(ctx context.Context) error {
+		header, err := pmcontext.GetAuthHeaderFromContext(ctx)
+		if err != nil {
+			return ErrUnauthenticated
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 2 || !strings.EqualFold(fields[0], "bearer") {
+			return ErrUnauthenticated
+		}
+
+		review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: fields[1]}}
+		if err := cl.Create(ctx, review); err != nil {
+			return fmt.Errorf("failed to review the token: %w", err)
+		}
+		if !review.Status.Authenticated {
+			return ErrUnauthenticated
+		}
+		if !slices.Contains(users, review.Status.User.Username) {
+			return fmt.Errorf("the user %s is not allowed to trigger reconciles", review.Status.User.Username)
+		}
+		return nil
+	}
+}
+
+// Request is the body of a trigger request
+type Request struct {
+	// Cluster is the name of the cluster of the object, only supported by multicluster controllers
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// Trigger serves an authenticated HTTP endpoint enqueuing reconciles on demand, so that forcing a reconcile needs
+// neither a write to the object nor RBAC on it. A POST of a Request to the endpoint enqueues a reconcile of the object
+// into the controller the Source or ClusterSource of the trigger is registered with.
+// Trigger implements manager.Runnable and has to be added to the manager.
+type Trigger struct {
+	cfg Config
+	log *logger.Logger
+
+	requests        chan event.TypedGenericEvent[reconcile.Request]
+	clusterRequests chan event.TypedGenericEvent[mcreconcile.Request]
+	sourced         atomic.Bool
+	clusterSourced  atomic.Bool
+}
+
+func NewTrigger(log *logger.Logger, opts ...Option) (*Trigger, error) {
+	cfg := NewConfig(opts...)
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &Trigger{
+		cfg:             cfg,
+		log:             log.ComponentLogger("trigger"),
+		requests:        make(chan event.TypedGenericEvent[reconcile.Request]),
+		clusterRequests: make(chan event.TypedGenericEvent[mcreconcile.Request]),
+	}, nil
+}
+
+// Source returns a source enqueuing the triggered reconciles into a controller-runtime controller
+func (t *Trigger) Source() source.Source {
+	t.sourced.Store(true)
+	return source.TypedChannel(t.requests, handler.TypedFuncs[reconcile.Request, reconcile.Request]{
+		GenericFunc: func(_ context.Context, e event.TypedGenericEvent[reconcile.Request], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			q.Add(e.Object)
+		},
+	})
+}
+
+// ClusterSource returns a source enqueuing the triggered reconciles into a multicluster controller
+func (t *Trigger) ClusterSource() source.TypedSource[mcreconcile.Request] {
+	t.clusterSourced.Store(true)
+	return source.TypedChannel(t.clusterRequests, handler.TypedFuncs[mcreconcile.Request, mcreconcile.Request]{
+		GenericFunc: func(_ context.Context, e event.TypedGenericEvent[mcreconcile.Request], q workqueue.TypedRateLimitingInterface[mcreconcile.Request]) {
+			q.Add(e.Object)
+		},
+	})
+}
+
+// Handler returns the handler of the endpoint, wrapped by the middlewares of middleware.CreateMiddleware including
+// the auth middlewares
+func (t *Trigger) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(t.cfg.Path, t.serveHTTP)
+
+	var h http.Handler = mux
+	mws := middleware.CreateMiddleware(t.log, true)
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// Start serves the endpoint until the context is done
+func (t *Trigger) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", t.cfg.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", t.cfg.Address, err)
+	}
+	server := &http.Server{Handler: t.Handler(), ReadHeaderTimeout: t.cfg.EnqueueTimeout}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+	t.log.Info().Str("address", t.cfg.Address).Str("path", t.cfg.Path).Msg("serving reconcile trigger")
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// NeedLeaderElection returns false, so that every replica serves the endpoint. Replicas not running the controller
+// answer with 503 Service Unavailable.
+func (t *Trigger) NeedLeaderElection() bool {
+	return false
+}
+
+func (t *Trigger) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := t.cfg.Authorize(r.Context()); err != nil {
+		if errors.Is(err, ErrUnauthenticated) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	var req Request
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<12))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "the name is required", http.StatusBadRequest)
+		return
+	}
+
+	status, err := t.enqueue(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	logger.LoadLoggerFromContext(r.Context()).Info().Str("cluster", req.Cluster).Str("namespace", req.Namespace).Str("name", req.Name).Msg("triggered reconcile")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// enqueue passes the request to the registered source and returns the status code and error if it cannot be enqueued
+func (t *Trigger) enqueue(ctx context.Context, req Request) (int, error) {
+	key := types.NamespacedName{Namespace: req.Namespace, Name: req.Name}
+	timeout := time.NewTimer(t.cfg.EnqueueTimeout)
+	defer timeout.Stop()
+
+	switch {
+	case t.clusterSourced.Load():
+		if req.Cluster == "" {
+			return http.StatusBadRequest, fmt.Errorf("the cluster is required")
+		}
+		e := event.TypedGenericEvent[mcreconcile.Request]{Object: mcreconcile.Request{ClusterName: req.Cluster, Request: reconcile.Request{NamespacedName: key}}}
+		select {
+		case t.clusterRequests <- e:
+			return http.StatusAccepted, nil
+		case <-timeout.C:
+		case <-ctx.Done():
+		}
+	case t.sourced.Load():
+		if req.Cluster != "" {
+			return http.StatusBadRequest, fmt.Errorf("the controller does not support clusters")
+		}
+		select {
+		case t.requests <- event.TypedGenericEvent[reconcile.Request]{Object: reconcile.Request{NamespacedName: key}}:
+			return http.StatusAccepted, nil
+		case <-timeout.C:
+		case <-ctx.Done():
+		}
+	}
+	return http.StatusServiceUnavailable, fmt.Errorf("the controller is not running")
+}
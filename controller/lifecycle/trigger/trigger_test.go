@@ -0,0 +1,216 @@
+package trigger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	mcreconcile "sigs.k8s.io/multicluster-runtime/pkg/reconcile"
+
+	pmcontext "github.com/platform-mesh/golang-commons/context"
+	"github.com/platform-mesh/golang-commons/jwt"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+const callerID = "spiffe://cluster.local/ns/default/sa/caller"
+
+func newTestTrigger(t *testing.T, opts ...Option) *Trigger {
+	opts = append([]Option{WithAuthorizer(AllowSpiffeIDs(callerID))}, opts...)
+	tr, err := NewTrigger(testlogger.New().Logger, opts...)
+	require.NoError(t, err)
+	return tr
+}
+
+func post(h http.Handler, spiffe string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/reconcile", strings.NewReader(body))
+	if spiffe != "" {
+		req.Header.Set(jwt.HeaderSpiffeValue, "Subject=\"CN=caller\";URI="+spiffe)
+	}
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestNewTrigger(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		err  string
+	}{
+		{name: "valid config", opts: []Option{WithAuthorizer(AllowSpiffeIDs(callerID))}},
+		{name: "missing authorizer", err: "an authorizer is required"},
+		{name: "empty address", opts: []Option{WithAuthorizer(AllowSpiffeIDs(callerID)), WithAddress("")}, err: "the address should not be empty"},
+		{name: "relative path", opts: []Option{WithAuthorizer(AllowSpiffeIDs(callerID)), WithPath("reconcile")}, err: "the path should start with /"},
+		{name: "zero enqueue timeout", opts: []Option{WithAuthorizer(AllowSpiffeIDs(callerID)), WithEnqueueTimeout(0)}, err: "the enqueue timeout should be positive"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewTrigger(testlogger.New().Logger, test.opts...)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestConfigDefaultsToLoopback(t *testing.T) {
+	assert.Equal(t, "127.0.0.1:8091", NewConfig().Address)
+}
+
+func TestAllowServiceAccounts(t *testing.T) {
+	cl := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			review := obj.(*authenticationv1.TokenReview)
+			switch review.Spec.Token {
+			case "caller-token":
+				review.Status = authenticationv1.TokenReviewStatus{Authenticated: true, User: authenticationv1.UserInfo{Username: "system:serviceaccount:default:caller"}}
+			case "other-token":
+				review.Status = authenticationv1.TokenReviewStatus{Authenticated: true, User: authenticationv1.UserInfo{Username: "system:serviceaccount:default:other"}}
+			}
+			return nil
+		},
+	}).Build()
+	authorize := AllowServiceAccounts(cl, types.NamespacedName{Namespace: "default", Name: "caller"})
+
+	tests := []struct {
+		name   string
+		header string
+		err    error
+	}{
+		{name: "verified service account", header: "Bearer caller-token"},
+		{name: "without header", err: ErrUnauthenticated},
+		{name: "other scheme", header: "Basic caller-token", err: ErrUnauthenticated},
+		{name: "unverified token", header: "Bearer forged-token", err: ErrUnauthenticated},
+		{name: "other service account", header: "Bearer other-token", err: errors.New("the user system:serviceaccount:default:other is not allowed to trigger reconciles")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.header != "" {
+				ctx = pmcontext.AddAuthHeaderToContext(ctx, test.header)
+			}
+
+			err := authorize(ctx)
+
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestTrigger_Source(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tr := newTestTrigger(t)
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+	require.NoError(t, tr.Source().Start(ctx, queue))
+	h := tr.Handler()
+
+	t.Run("enqueues the object", func(t *testing.T) {
+		recorder := post(h, callerID, `{"namespace":"bar","name":"foo"}`)
+
+		assert.Equal(t, http.StatusAccepted, recorder.Code)
+		assert.Eventually(t, func() bool { return queue.Len() == 1 }, time.Second, 10*time.Millisecond)
+		item, _ := queue.Get()
+		queue.Done(item)
+		assert.Equal(t, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "bar", Name: "foo"}}, item)
+	})
+
+	t.Run("rejects requests", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			spiffe string
+			body   string
+			code   int
+		}{
+			{name: "without identity", body: `{"name":"foo"}`, code: http.StatusUnauthorized},
+			{name: "of another workload", spiffe: "spiffe://cluster.local/ns/default/sa/other", body: `{"name":"foo"}`, code: http.StatusForbidden},
+			{name: "with unknown fields", spiffe: callerID, body: `{"name":"foo","kind":"Account"}`, code: http.StatusBadRequest},
+			{name: "without name", spiffe: callerID, body: `{"namespace":"bar"}`, code: http.StatusBadRequest},
+			{name: "with a cluster", spiffe: callerID, body: `{"cluster":"root","name":"foo"}`, code: http.StatusBadRequest},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				recorder := post(h, test.spiffe, test.body)
+
+				assert.Equal(t, test.code, recorder.Code)
+				assert.Equal(t, 0, queue.Len())
+			})
+		}
+	})
+
+	t.Run("rejects other methods", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/reconcile", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	})
+}
+
+func TestTrigger_ClusterSource(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tr := newTestTrigger(t)
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[mcreconcile.Request]())
+	defer queue.ShutDown()
+	require.NoError(t, tr.ClusterSource().Start(ctx, queue))
+
+	// Act
+	missingCluster := post(tr.Handler(), callerID, `{"name":"foo"}`)
+	recorder := post(tr.Handler(), callerID, `{"cluster":"root:orgs","name":"foo"}`)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, missingCluster.Code)
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Eventually(t, func() bool { return queue.Len() == 1 }, time.Second, 10*time.Millisecond)
+	item, _ := queue.Get()
+	assert.Equal(t, mcreconcile.Request{ClusterName: "root:orgs", Request: reconcile.Request{NamespacedName: types.NamespacedName{Name: "foo"}}}, item)
+}
+
+func TestTrigger_WithoutController(t *testing.T) {
+	// Arrange
+	tr := newTestTrigger(t, WithEnqueueTimeout(10*time.Millisecond))
+	_ = tr.Source()
+
+	// Act
+	recorder := post(tr.Handler(), callerID, `{"name":"foo"}`)
+
+	// Assert
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestTrigger_Start(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	tr := newTestTrigger(t, WithAddress("127.0.0.1:0"))
+	done := make(chan error, 1)
+
+	// Act
+	go func() { done <- tr.Start(ctx) }()
+	cancel()
+
+	// Assert
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("trigger did not stop")
+	}
+	assert.False(t, tr.NeedLeaderElection())
+}
@@ -0,0 +1,19 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// HashInputs returns a stable hash of the JSON representation of the given inputs
+func HashInputs(inputs ...any) (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	for _, input := range inputs {
+		if err := enc.Encode(input); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
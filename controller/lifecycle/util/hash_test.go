@@ -0,0 +1,24 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashInputs(t *testing.T) {
+	hash, err := HashInputs("a", map[string]int{"b": 1, "c": 2})
+	require.NoError(t, err)
+
+	same, err := HashInputs("a", map[string]int{"c": 2, "b": 1})
+	require.NoError(t, err)
+	assert.Equal(t, hash, same)
+
+	other, err := HashInputs("a", map[string]int{"b": 1})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+
+	_, err = HashInputs(func() {})
+	assert.Error(t, err)
+}
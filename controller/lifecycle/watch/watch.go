@@ -0,0 +1,164 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/platform-mesh/golang-commons/logger"
+)
+
+// Mapping defines how events of a secondary object are mapped back to the primary object
+type Mapping int
+
+const (
+	// MapByOwnerReference enqueues the controlling owner of the secondary object
+	MapByOwnerReference Mapping = iota
+	// MapByLabel enqueues the primary object referenced by labels on the secondary object
+	MapByLabel
+	// MapByIndexedField enqueues all primary objects whose indexed field matches the secondary object
+	MapByIndexedField
+)
+
+// ValueFunc returns the index values of a secondary object that are looked up in the primary object index
+type ValueFunc func(obj client.Object) []string
+
+// Watch describes a secondary resource a subroutine reads or owns
+type Watch struct {
+	// Object is the type of the secondary resource, used to resolve its GVK
+	Object  client.Object
+	Mapping Mapping
+
+	// NameLabel and NamespaceLabel are used by MapByLabel. If NamespaceLabel is empty,
+	// the namespace of the secondary object is used.
+	NameLabel      string
+	NamespaceLabel string
+
+	// IndexField, IndexFunc and ValueFunc are used by MapByIndexedField. IndexFunc is
+	// registered as field index on the primary object, ValueFunc extracts the values
+	// to look up from the secondary object.
+	IndexField string
+	IndexFunc  client.IndexerFunc
+	ValueFunc  ValueFunc
+
+	Predicates []predicate.Predicate
+}
+
+// Owns watches obj and enqueues its controlling owner
+func Owns(obj client.Object, predicates ...predicate.Predicate) Watch {
+	return Watch{Object: obj, Mapping: MapByOwnerReference, Predicates: predicates}
+}
+
+// ByLabel watches obj and enqueues the primary object named by the nameLabel and namespaceLabel labels
+func ByLabel(obj client.Object, nameLabel, namespaceLabel string, predicates ...predicate.Predicate) Watch {
+	return Watch{Object: obj, Mapping: MapByLabel, NameLabel: nameLabel, NamespaceLabel: namespaceLabel, Predicates: predicates}
+}
+
+// ByIndexedField watches obj and enqueues all primary objects where the field index registered
+// with indexFunc contains one of the values returned by valueFunc for the secondary object
+func ByIndexedField(obj client.Object, field string, indexFunc client.IndexerFunc, valueFunc ValueFunc, predicates ...predicate.Predicate) Watch {
+	return Watch{Object: obj, Mapping: MapByIndexedField, IndexField: field, IndexFunc: indexFunc, ValueFunc: valueFunc, Predicates: predicates}
+}
+
+// Validate checks that all fields required by the mapping are set
+func (w Watch) Validate() error {
+	if w.Object == nil {
+		return fmt.Errorf("watch object must not be nil")
+	}
+	switch w.Mapping {
+	case MapByOwnerReference:
+		return nil
+	case MapByLabel:
+		if w.NameLabel == "" {
+			return fmt.Errorf("watch for %T maps by label but has no name label", w.Object)
+		}
+		return nil
+	case MapByIndexedField:
+		if w.IndexField == "" || w.IndexFunc == nil || w.ValueFunc == nil {
+			return fmt.Errorf("watch for %T maps by indexed field but index field, index func or value func is missing", w.Object)
+		}
+		return nil
+	default:
+		return fmt.Errorf("watch for %T has unknown mapping %d", w.Object, w.Mapping)
+	}
+}
+
+// MapFunc returns a handler.MapFunc mapping secondary objects to requests for the primary object.
+// It is only valid for MapByLabel and MapByIndexedField, owner references are handled by the builders.
+func (w Watch) MapFunc(cl client.Reader, scheme *runtime.Scheme, primary client.Object, log *logger.Logger) (handler.MapFunc, error) {
+	switch w.Mapping {
+	case MapByLabel:
+		return w.mapByLabel, nil
+	case MapByIndexedField:
+		list, err := newListFor(scheme, primary)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, obj client.Object) []reconcile.Request {
+			return w.mapByIndexedField(ctx, cl, list, obj, log)
+		}, nil
+	default:
+		return nil, fmt.Errorf("watch for %T has no map func for mapping %d", w.Object, w.Mapping)
+	}
+}
+
+func (w Watch) mapByLabel(_ context.Context, obj client.Object) []reconcile.Request {
+	name, ok := obj.GetLabels()[w.NameLabel]
+	if !ok || name == "" {
+		return nil
+	}
+	namespace := obj.GetNamespace()
+	if w.NamespaceLabel != "" {
+		namespace = obj.GetLabels()[w.NamespaceLabel]
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+}
+
+func (w Watch) mapByIndexedField(ctx context.Context, cl client.Reader, list client.ObjectList, obj client.Object, log *logger.Logger) []reconcile.Request {
+	var requests []reconcile.Request
+	seen := map[types.NamespacedName]struct{}{}
+	for _, value := range w.ValueFunc(obj) {
+		l := list.DeepCopyObject().(client.ObjectList)
+		if err := cl.List(ctx, l, client.MatchingFields{w.IndexField: value}); err != nil {
+			log.Error().Err(err).Str("field", w.IndexField).Str("value", value).Msg("failed to list objects by indexed field")
+			continue
+		}
+		_ = meta.EachListItem(l, func(o runtime.Object) error {
+			mo, err := meta.Accessor(o)
+			if err != nil {
+				return nil
+			}
+			key := types.NamespacedName{Name: mo.GetName(), Namespace: mo.GetNamespace()}
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				requests = append(requests, reconcile.Request{NamespacedName: key})
+			}
+			return nil
+		})
+	}
+	return requests
+}
+
+func newListFor(scheme *runtime.Scheme, obj client.Object) (client.ObjectList, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	o, err := scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return nil, err
+	}
+	list, ok := o.(client.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("type %T is not a client.ObjectList", o)
+	}
+	return list, nil
+}
@@ -0,0 +1,127 @@
+package watch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+const secretRefField = "spec.secretRef"
+
+func secretRefIndex(obj client.Object) []string {
+	return []string{obj.GetAnnotations()["secret"]}
+}
+
+func secretName(obj client.Object) []string {
+	return []string{obj.GetName()}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		watch   Watch
+		wantErr bool
+	}{
+		{name: "owns", watch: Owns(&corev1.Secret{})},
+		{name: "by label", watch: ByLabel(&corev1.Secret{}, "name", "")},
+		{name: "by label without name label", watch: ByLabel(&corev1.Secret{}, "", "namespace"), wantErr: true},
+		{name: "by indexed field", watch: ByIndexedField(&corev1.Secret{}, secretRefField, secretRefIndex, secretName)},
+		{name: "by indexed field without value func", watch: ByIndexedField(&corev1.Secret{}, secretRefField, secretRefIndex, nil), wantErr: true},
+		{name: "nil object", watch: Watch{}, wantErr: true},
+		{name: "unknown mapping", watch: Watch{Object: &corev1.Secret{}, Mapping: Mapping(42)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.watch.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMapFunc(t *testing.T) {
+	ctx := context.Background()
+	log := testlogger.New()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	t.Run("maps by label using the namespace of the secondary object", func(t *testing.T) {
+		w := ByLabel(&corev1.Secret{}, "owner-name", "")
+		mapFunc, err := w.MapFunc(nil, scheme, &corev1.ConfigMap{}, log.Logger)
+		require.NoError(t, err)
+
+		requests := mapFunc(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s", Namespace: "ns", Labels: map[string]string{"owner-name": "cm"}}})
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, types.NamespacedName{Name: "cm", Namespace: "ns"}, requests[0].NamespacedName)
+	})
+
+	t.Run("maps by label using the namespace label", func(t *testing.T) {
+		w := ByLabel(&corev1.Secret{}, "owner-name", "owner-namespace")
+		mapFunc, err := w.MapFunc(nil, scheme, &corev1.ConfigMap{}, log.Logger)
+		require.NoError(t, err)
+
+		requests := mapFunc(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s", Namespace: "ns", Labels: map[string]string{"owner-name": "cm", "owner-namespace": "other"}}})
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, types.NamespacedName{Name: "cm", Namespace: "other"}, requests[0].NamespacedName)
+	})
+
+	t.Run("ignores objects without the name label", func(t *testing.T) {
+		w := ByLabel(&corev1.Secret{}, "owner-name", "")
+		mapFunc, err := w.MapFunc(nil, scheme, &corev1.ConfigMap{}, log.Logger)
+		require.NoError(t, err)
+
+		requests := mapFunc(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s", Namespace: "ns"}})
+
+		assert.Empty(t, requests)
+	})
+
+	t.Run("maps by indexed field", func(t *testing.T) {
+		cl := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithIndex(&corev1.ConfigMap{}, secretRefField, secretRefIndex).
+			WithObjects(
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "ns", Annotations: map[string]string{"secret": "s"}}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "ns", Annotations: map[string]string{"secret": "s"}}},
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "ns", Annotations: map[string]string{"secret": "other"}}},
+			).Build()
+
+		w := ByIndexedField(&corev1.Secret{}, secretRefField, secretRefIndex, secretName)
+		mapFunc, err := w.MapFunc(cl, scheme, &corev1.ConfigMap{}, log.Logger)
+		require.NoError(t, err)
+
+		requests := mapFunc(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "s", Namespace: "ns"}})
+
+		require.Len(t, requests, 2)
+		assert.ElementsMatch(t, []types.NamespacedName{{Name: "a", Namespace: "ns"}, {Name: "b", Namespace: "ns"}},
+			[]types.NamespacedName{requests[0].NamespacedName, requests[1].NamespacedName})
+	})
+
+	t.Run("fails for primary objects without a registered list type", func(t *testing.T) {
+		w := ByIndexedField(&corev1.Secret{}, secretRefField, secretRefIndex, secretName)
+		_, err := w.MapFunc(nil, runtime.NewScheme(), &corev1.ConfigMap{}, log.Logger)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("fails for owner reference mappings", func(t *testing.T) {
+		_, err := Owns(&corev1.Secret{}).MapFunc(nil, scheme, &corev1.ConfigMap{}, log.Logger)
+
+		assert.Error(t, err)
+	})
+}
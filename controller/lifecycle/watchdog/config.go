@@ -0,0 +1,64 @@
+package watchdog
+
+import (
+	"fmt"
+	"time"
+)
+
+type Config struct {
+	// Threshold is the duration after which an in-flight reconcile is considered stuck
+	Threshold time.Duration
+	// MaxStuckReconciles is the number of stuck reconciles at which the health check fails
+	MaxStuckReconciles int
+	// CheckInterval is the interval in which stuck reconciles are logged and reported
+	CheckInterval time.Duration
+}
+
+var defaultConfig = Config{
+	Threshold:          10 * time.Minute,
+	MaxStuckReconciles: 1,
+	CheckInterval:      30 * time.Second,
+}
+
+func (c Config) validate() error {
+	if c.Threshold <= 0 {
+		return fmt.Errorf("the threshold should be positive")
+	}
+	if c.MaxStuckReconciles <= 0 {
+		return fmt.Errorf("the max stuck reconciles should be positive")
+	}
+	if c.CheckInterval <= 0 {
+		return fmt.Errorf("the check interval should be positive")
+	}
+	return nil
+}
+
+type Option func(*Config)
+
+func WithThreshold(d time.Duration) Option {
+	return func(c *Config) {
+		c.Threshold = d
+	}
+}
+
+func WithMaxStuckReconciles(n int) Option {
+	return func(c *Config) {
+		c.MaxStuckReconciles = n
+	}
+}
+
+func WithCheckInterval(d time.Duration) Option {
+	return func(c *Config) {
+		c.CheckInterval = d
+	}
+}
+
+func NewConfig(options ...Option) Config {
+	cfg := defaultConfig
+
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	return cfg
+}
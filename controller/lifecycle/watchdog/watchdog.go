@@ -0,0 +1,155 @@
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/platform-mesh/golang-commons/sentry"
+)
+
+// InFlightReconcile describes a reconcile currently being processed
+type InFlightReconcile struct {
+	Object     string
+	Subroutine string
+	Start      time.Time
+}
+
+// Watchdog tracks in-flight reconciles and detects reconciles exceeding the configured threshold.
+// It implements manager.Runnable to periodically log and report stuck reconciles to Sentry and
+// provides a healthz.Checker failing once too many reconciles are stuck.
+type Watchdog struct {
+	cfg Config
+	log *logger.Logger
+	now func() time.Time
+
+	mu       sync.Mutex
+	nextID   uint64
+	inFlight map[uint64]*trackedReconcile
+}
+
+type trackedReconcile struct {
+	w        *Watchdog
+	id       uint64
+	tags     sentry.Tags
+	reported bool
+	InFlightReconcile
+}
+
+func NewWatchdog(log *logger.Logger, opts ...Option) (*Watchdog, error) {
+	cfg := NewConfig(opts...)
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &Watchdog{
+		cfg:      cfg,
+		log:      log.ComponentLogger("watchdog"),
+		now:      time.Now,
+		inFlight: map[uint64]*trackedReconcile{},
+	}, nil
+}
+
+// Track registers a new in-flight reconcile for the given object
+func (w *Watchdog) Track(object string, tags sentry.Tags) api.TrackedReconcile {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.nextID++
+	t := &trackedReconcile{w: w, id: w.nextID, tags: tags, InFlightReconcile: InFlightReconcile{Object: object, Start: w.now()}}
+	w.inFlight[t.id] = t
+	return t
+}
+
+func (t *trackedReconcile) SetSubroutine(name string) {
+	t.w.mu.Lock()
+	defer t.w.mu.Unlock()
+	t.Subroutine = name
+}
+
+func (t *trackedReconcile) Done() {
+	t.w.mu.Lock()
+	defer t.w.mu.Unlock()
+	delete(t.w.inFlight, t.id)
+}
+
+// Stuck returns all in-flight reconciles running longer than the threshold, oldest first
+func (w *Watchdog) Stuck() []InFlightReconcile {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var stuck []InFlightReconcile
+	for _, t := range w.stuck() {
+		stuck = append(stuck, t.InFlightReconcile)
+	}
+	return stuck
+}
+
+func (w *Watchdog) stuck() []*trackedReconcile {
+	now := w.now()
+	var stuck []*trackedReconcile
+	for _, t := range w.inFlight {
+		if now.Sub(t.Start) >= w.cfg.Threshold {
+			stuck = append(stuck, t)
+		}
+	}
+	sort.Slice(stuck, func(i, j int) bool { return stuck[i].Start.Before(stuck[j].Start) })
+	return stuck
+}
+
+// Check logs and reports all reconciles which became stuck since the last check
+func (w *Watchdog) Check() {
+	w.mu.Lock()
+	var newlyStuck []trackedReconcile
+	for _, t := range w.stuck() {
+		if !t.reported {
+			t.reported = true
+			newlyStuck = append(newlyStuck, *t)
+		}
+	}
+	now := w.now()
+	w.mu.Unlock()
+
+	for _, t := range newlyStuck {
+		duration := now.Sub(t.Start)
+		w.log.Error().Str("object", t.Object).Str("subroutine", t.Subroutine).Dur("duration", duration).Msg("reconcile is stuck")
+		sentry.CaptureError(fmt.Errorf("reconcile of %s stuck in subroutine %q for %s", t.Object, t.Subroutine, duration), t.tags,
+			sentry.Extras{"object": t.Object, "subroutine": t.Subroutine, "start": t.Start})
+	}
+}
+
+// Checker returns a healthz.Checker failing once MaxStuckReconciles reconciles are stuck
+func (w *Watchdog) Checker() healthz.Checker {
+	return func(_ *http.Request) error {
+		stuck := w.Stuck()
+		if len(stuck) >= w.cfg.MaxStuckReconciles {
+			return fmt.Errorf("%d reconciles are stuck, the oldest in subroutine %q of %s since %s", len(stuck), stuck[0].Subroutine, stuck[0].Object, stuck[0].Start.Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+// Start periodically checks for stuck reconciles until the context is done
+func (w *Watchdog) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.cfg.CheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// NeedLeaderElection returns false, since every replica has to watch its own reconciles
+func (w *Watchdog) NeedLeaderElection() bool {
+	return false
+}
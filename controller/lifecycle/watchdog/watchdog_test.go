@@ -0,0 +1,113 @@
+package watchdog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func TestNewWatchdog(t *testing.T) {
+	log := testlogger.New()
+
+	_, err := NewWatchdog(log.Logger)
+	assert.NoError(t, err)
+
+	_, err = NewWatchdog(log.Logger, WithThreshold(0))
+	assert.Error(t, err)
+
+	_, err = NewWatchdog(log.Logger, WithMaxStuckReconciles(0))
+	assert.Error(t, err)
+
+	_, err = NewWatchdog(log.Logger, WithCheckInterval(-time.Second))
+	assert.Error(t, err)
+}
+
+func TestWatchdog(t *testing.T) {
+	log := testlogger.New()
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	w, err := NewWatchdog(log.Logger, WithThreshold(time.Minute), WithMaxStuckReconciles(2))
+	require.NoError(t, err)
+	w.now = func() time.Time { return now }
+	checker := w.Checker()
+
+	first := w.Track("bar/first", nil)
+	first.SetSubroutine("slow")
+	now = now.Add(30 * time.Second)
+	second := w.Track("bar/second", nil)
+	second.SetSubroutine("slower")
+
+	t.Run("does not report reconciles below the threshold", func(t *testing.T) {
+		assert.Empty(t, w.Stuck())
+		assert.NoError(t, checker(nil))
+	})
+
+	t.Run("reports reconciles exceeding the threshold", func(t *testing.T) {
+		now = now.Add(30 * time.Second)
+
+		stuck := w.Stuck()
+
+		require.Len(t, stuck, 1)
+		assert.Equal(t, "bar/first", stuck[0].Object)
+		assert.Equal(t, "slow", stuck[0].Subroutine)
+		assert.NoError(t, checker(nil))
+
+		w.Check()
+		w.Check()
+		assert.Equal(t, 1, countLogMessages(t, log, "reconcile is stuck"))
+	})
+
+	t.Run("fails the health check once too many reconciles are stuck", func(t *testing.T) {
+		now = now.Add(30 * time.Second)
+
+		require.Len(t, w.Stuck(), 2)
+		assert.ErrorContains(t, checker(nil), "2 reconciles are stuck")
+	})
+
+	t.Run("recovers once stuck reconciles finish", func(t *testing.T) {
+		first.Done()
+		second.Done()
+
+		assert.Empty(t, w.Stuck())
+		assert.NoError(t, checker(nil))
+	})
+}
+
+func TestWatchdog_Start(t *testing.T) {
+	log := testlogger.New()
+	w, err := NewWatchdog(log.Logger, WithThreshold(time.Millisecond), WithCheckInterval(time.Millisecond))
+	require.NoError(t, err)
+	assert.False(t, w.NeedLeaderElection())
+
+	tracked := w.Track("bar/foo", nil)
+	defer tracked.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Start(ctx) }()
+
+	assert.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return tracked.(*trackedReconcile).reported
+	}, time.Second, time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, countLogMessages(t, log, "reconcile is stuck"))
+}
+
+func countLogMessages(t *testing.T, log *testlogger.TestLogger, msg string) int {
+	messages, err := log.GetLogMessages()
+	require.NoError(t, err)
+	count := 0
+	for _, m := range messages {
+		if m.Message == msg {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,75 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+	"github.com/platform-mesh/golang-commons/sentry"
+)
+
+type recordingTracker struct {
+	objects     []string
+	subroutines []string
+	done        int
+}
+
+func (r *recordingTracker) Track(object string, _ sentry.Tags) api.TrackedReconcile {
+	r.objects = append(r.objects, object)
+	return r
+}
+
+func (r *recordingTracker) SetSubroutine(name string) { r.subroutines = append(r.subroutines, name) }
+func (r *recordingTracker) Done()                     { r.done++ }
+
+func TestReconcileTracking(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New()
+
+	instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+	fakeClient := pmtesting.CreateFakeClient(t, instance)
+	tracker := &recordingTracker{}
+	mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{
+		pmtesting.ChangeStatusSubroutine{},
+		pmtesting.ContextValueSubroutine{},
+	}}).WithReconcileTracker(tracker)
+
+	_, err := Reconcile(context.WithValue(ctx, pmtesting.ContextValueKey, "value"), nName, instance, fakeClient, mgr)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bar/foo"}, tracker.objects)
+	assert.Equal(t, []string{"changeStatus", "ContextValueSubroutine"}, tracker.subroutines)
+	assert.Equal(t, 1, tracker.done)
+}
+
+func TestReconcileTrackingFromContext(t *testing.T) {
+	ctx := context.Background()
+	nName := types.NamespacedName{Name: "foo", Namespace: "bar"}
+	log := testlogger.New()
+
+	instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: nName.Name, Namespace: nName.Namespace}}
+	fakeClient := pmtesting.CreateFakeClient(t, instance)
+	managerTracker := &recordingTracker{}
+	contextTracker := &recordingTracker{}
+	mgr := (&pmtesting.TestLifecycleManager{Logger: log.Logger, SubroutinesArr: []subroutine.Subroutine{
+		pmtesting.ChangeStatusSubroutine{},
+	}}).WithReconcileTracker(managerTracker)
+
+	_, err := Reconcile(SetReconcileTrackerInContext(ctx, contextTracker), nName, instance, fakeClient, mgr)
+
+	require.NoError(t, err)
+	for _, tracker := range []*recordingTracker{managerTracker, contextTracker} {
+		assert.Equal(t, []string{"bar/foo"}, tracker.objects)
+		assert.Equal(t, []string{"changeStatus"}, tracker.subroutines)
+		assert.Equal(t, 1, tracker.done)
+	}
+}
@@ -0,0 +1,94 @@
+package testSupport
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// AdmissionHarness feeds admission requests to an admission handler without an API server
+type AdmissionHarness struct {
+	t       *testing.T
+	handler admission.Handler
+	scheme  *runtime.Scheme
+}
+
+// NewAdmissionHarness creates a harness for the handler. The scheme has to contain the types of all objects sent.
+func NewAdmissionHarness(t *testing.T, handler admission.Handler, scheme *runtime.Scheme) *AdmissionHarness {
+	return &AdmissionHarness{t: t, handler: handler, scheme: scheme}
+}
+
+func (h *AdmissionHarness) Create(obj client.Object) admission.Response {
+	return h.Handle(CreateAdmissionRequest(h.t, h.scheme, admissionv1.Create, obj, nil))
+}
+
+func (h *AdmissionHarness) Update(oldObj, newObj client.Object) admission.Response {
+	return h.Handle(CreateAdmissionRequest(h.t, h.scheme, admissionv1.Update, newObj, oldObj))
+}
+
+func (h *AdmissionHarness) Delete(obj client.Object) admission.Response {
+	return h.Handle(CreateAdmissionRequest(h.t, h.scheme, admissionv1.Delete, nil, obj))
+}
+
+func (h *AdmissionHarness) Handle(req admission.Request) admission.Response {
+	return h.handler.Handle(context.Background(), req)
+}
+
+// CreateAdmissionRequest creates an admission request for the operation. Name, namespace and kind are taken
+// from obj, or oldObj if obj is nil.
+func CreateAdmissionRequest(t *testing.T, scheme *runtime.Scheme, operation admissionv1.Operation, obj, oldObj client.Object) admission.Request {
+	meta := obj
+	if meta == nil {
+		meta = oldObj
+	}
+	gvk, err := apiutil.GVKForObject(meta, scheme)
+	require.NoError(t, err)
+
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		UID:       types.UID("test-uid"),
+		Kind:      metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+		Name:      meta.GetName(),
+		Namespace: meta.GetNamespace(),
+		Operation: operation,
+		Object:    rawExtension(t, scheme, obj),
+		OldObject: rawExtension(t, scheme, oldObj),
+	}}
+}
+
+func rawExtension(t *testing.T, scheme *runtime.Scheme, obj client.Object) runtime.RawExtension {
+	if obj == nil {
+		return runtime.RawExtension{}
+	}
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	require.NoError(t, err)
+	obj = obj.DeepCopyObject().(client.Object)
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+	return runtime.RawExtension{Raw: raw}
+}
+
+// ApplyPatches applies the JSON patches of an admission response to obj
+func ApplyPatches(t *testing.T, resp admission.Response, obj client.Object) {
+	original, err := json.Marshal(obj)
+	require.NoError(t, err)
+	patchJson, err := json.Marshal(resp.Patches)
+	require.NoError(t, err)
+	patch, err := jsonpatch.DecodePatch(patchJson)
+	require.NoError(t, err)
+
+	patched, err := patch.Apply(original)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(patched, obj))
+}
@@ -13,16 +13,23 @@ import (
 
 func CreateFakeClient(t *testing.T, objects ...client.Object) client.WithWatch {
 	builder := fake.NewClientBuilder()
+	for _, obj := range objects {
+		builder.WithStatusSubresource(obj)
+	}
+	builder.WithScheme(CreateScheme(t, objects...))
+	builder.WithObjects(objects...)
+	return builder.Build()
+}
+
+// CreateScheme creates a scheme registering TestApiObject and the types of the given objects in the test group
+func CreateScheme(t *testing.T, objects ...client.Object) *runtime.Scheme {
 	s := runtime.NewScheme()
 	sBuilder := scheme.Builder{GroupVersion: schema.GroupVersion{Group: "test.platform-mesh.io", Version: "v1alpha1"}}
 	sBuilder.Register(&TestApiObject{})
 	for _, obj := range objects {
 		sBuilder.Register(obj)
-		builder.WithStatusSubresource(obj)
 	}
 	err := sBuilder.AddToScheme(s)
 	assert.NoError(t, err)
-	builder.WithScheme(s)
-	builder.WithObjects(objects...)
-	return builder.Build()
+	return s
 }
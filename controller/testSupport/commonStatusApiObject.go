@@ -0,0 +1,77 @@
+package testSupport
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+)
+
+//go:generate go run ../cmd/lifecycle-gen
+
+// CommonStatusApiObject embeds api.CommonStatus and implements the lifecycle interfaces with generated methods
+// +lifecycle:commonstatus
+type CommonStatusApiObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status CommonStatusApiObjectStatus `json:"status,omitempty"`
+}
+
+type CommonStatusApiObjectStatus struct {
+	api.CommonStatus `json:",inline"`
+
+	Some string `json:"some,omitempty"`
+}
+
+func (t *CommonStatusApiObject) DeepCopyObject() runtime.Object {
+	if c := t.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+func (t *CommonStatusApiObject) DeepCopy() *CommonStatusApiObject {
+	if t == nil {
+		return nil
+	}
+	out := new(CommonStatusApiObject)
+	t.DeepCopyInto(out)
+	return out
+}
+func (m *CommonStatusApiObject) DeepCopyInto(out *CommonStatusApiObject) {
+	*out = *m
+	out.TypeMeta = m.TypeMeta
+	m.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	m.Status.CommonStatus.DeepCopyInto(&out.Status.CommonStatus)
+}
+
+// ImplementConditionsAndSpreadReconciles implements the condition and spread reconcile interfaces, along with the
+// other lifecycle interfaces, with generated methods
+// +lifecycle:commonstatus
+type ImplementConditionsAndSpreadReconciles struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status CommonStatusApiObjectStatus `json:"status,omitempty"`
+}
+
+func (t *ImplementConditionsAndSpreadReconciles) DeepCopyObject() runtime.Object {
+	if c := t.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+func (t *ImplementConditionsAndSpreadReconciles) DeepCopy() *ImplementConditionsAndSpreadReconciles {
+	if t == nil {
+		return nil
+	}
+	out := new(ImplementConditionsAndSpreadReconciles)
+	t.DeepCopyInto(out)
+	return out
+}
+func (m *ImplementConditionsAndSpreadReconciles) DeepCopyInto(out *ImplementConditionsAndSpreadReconciles) {
+	*out = *m
+	out.TypeMeta = m.TypeMeta
+	m.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	m.Status.CommonStatus.DeepCopyInto(&out.Status.CommonStatus)
+}
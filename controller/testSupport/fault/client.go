@@ -0,0 +1,174 @@
+package fault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Client wraps the client to inject faults into its calls, including the calls of its Status and SubResource
+// clients. Calls with an injected fault are not sent.
+func (i *Injector) Client(cl client.Client) client.Client {
+	return &faultyClient{Client: cl, injector: i}
+}
+
+type faultyClient struct {
+	client.Client
+	injector *Injector
+}
+
+func (c *faultyClient) inject(verb string, gvk schema.GroupVersionKind, key client.ObjectKey) error {
+	return c.injector.injectError(Call{Verb: verb, GVK: gvk, Key: key})
+}
+
+// gvkFor returns the group version kind of the object, or its go type if it is not known by the scheme
+func gvkFor(obj runtime.Object, scheme *runtime.Scheme) schema.GroupVersionKind {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return schema.GroupVersionKind{Kind: fmt.Sprintf("%T", obj)}
+	}
+	return gvk
+}
+
+func gvkForList(list client.ObjectList, scheme *runtime.Scheme) schema.GroupVersionKind {
+	gvk := gvkFor(list, scheme)
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	return gvk
+}
+
+// applyTarget returns the group version kind and the key of typed and unstructured apply configurations
+func applyTarget(obj runtime.ApplyConfiguration) (schema.GroupVersionKind, client.ObjectKey) {
+	gvk := schema.GroupVersionKind{Kind: fmt.Sprintf("%T", obj)}
+	if ac, ok := obj.(interface {
+		GetAPIVersion() *string
+		GetKind() *string
+	}); ok && ac.GetAPIVersion() != nil && ac.GetKind() != nil {
+		gvk = schema.FromAPIVersionAndKind(*ac.GetAPIVersion(), *ac.GetKind())
+	}
+	var key client.ObjectKey
+	if ac, ok := obj.(interface{ GetName() *string }); ok && ac.GetName() != nil {
+		key.Name = *ac.GetName()
+	}
+	if ac, ok := obj.(interface{ GetNamespace() *string }); ok && ac.GetNamespace() != nil {
+		key.Namespace = *ac.GetNamespace()
+	}
+	return gvk, key
+}
+
+func (c *faultyClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if err := c.inject("get", gvkFor(obj, c.Scheme()), key); err != nil {
+		return err
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
+
+func (c *faultyClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOpts := (&client.ListOptions{}).ApplyOptions(opts)
+	if err := c.inject("list", gvkForList(list, c.Scheme()), client.ObjectKey{Namespace: listOpts.Namespace}); err != nil {
+		return err
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func (c *faultyClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.inject("create", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *faultyClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := c.inject("delete", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *faultyClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.inject("update", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *faultyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.inject("patch", gvkFor(obj, c.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *faultyClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+	gvk, key := applyTarget(obj)
+	if err := c.inject("apply", gvk, key); err != nil {
+		return err
+	}
+	return c.Client.Apply(ctx, obj, opts...)
+}
+
+func (c *faultyClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	deleteOpts := (&client.DeleteAllOfOptions{}).ApplyOptions(opts)
+	if err := c.inject("deletecollection", gvkFor(obj, c.Scheme()), client.ObjectKey{Namespace: deleteOpts.Namespace}); err != nil {
+		return err
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *faultyClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *faultyClient) SubResource(subResource string) client.SubResourceClient {
+	return &faultySubResourceClient{SubResourceClient: c.Client.SubResource(subResource), client: c, subResource: subResource}
+}
+
+type faultySubResourceClient struct {
+	client.SubResourceClient
+	client      *faultyClient
+	subResource string
+}
+
+func (c *faultySubResourceClient) inject(verb string, gvk schema.GroupVersionKind, key client.ObjectKey) error {
+	return c.client.inject(verb+"/"+c.subResource, gvk, key)
+}
+
+func (c *faultySubResourceClient) Get(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	if err := c.inject("get", gvkFor(obj, c.client.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Get(ctx, obj, subResource, opts...)
+}
+
+func (c *faultySubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	if err := c.inject("create", gvkFor(obj, c.client.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+func (c *faultySubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if err := c.inject("update", gvkFor(obj, c.client.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+func (c *faultySubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if err := c.inject("patch", gvkFor(obj, c.client.Scheme()), client.ObjectKeyFromObject(obj)); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *faultySubResourceClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.SubResourceApplyOption) error {
+	gvk, key := applyTarget(obj)
+	if err := c.inject("apply", gvk, key); err != nil {
+		return err
+	}
+	return c.SubResourceClient.Apply(ctx, obj, opts...)
+}
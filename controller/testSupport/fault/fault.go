@@ -0,0 +1,314 @@
+// Package fault injects faults into subroutines and clients in tests. An Injector wraps subroutines and clients and
+// injects conflicts, timeouts, errors or panics into the calls matching its rules, so that tests can check that an
+// operator converges under failures without writing dedicated fake types.
+package fault
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	pmerrors "github.com/platform-mesh/golang-commons/errors"
+)
+
+// Phase is the lifecycle phase of a subroutine call
+type Phase string
+
+const (
+	PhaseProcess    Phase = "process"
+	PhaseFinalize   Phase = "finalize"
+	PhaseTerminate  Phase = "terminate"
+	PhaseInitialize Phase = "initialize"
+	PhaseInputHash  Phase = "inputhash"
+)
+
+// Call describes an intercepted call of a wrapped subroutine or client
+type Call struct {
+	// Subroutine is the name of the called subroutine, empty for client calls
+	Subroutine string
+	// Phase is the phase of subroutine calls, empty for client calls
+	Phase Phase
+	// Verb is the verb of client calls, e.g. get, update or patch/status, empty for subroutine calls
+	Verb string
+	GVK  schema.GroupVersionKind
+	Key  client.ObjectKey
+}
+
+// Matcher restricts a rule to matching calls
+type Matcher func(call Call) bool
+
+// OnSubroutine matches calls of the named subroutines
+func OnSubroutine(names ...string) Matcher {
+	return func(call Call) bool {
+		return slices.Contains(names, call.Subroutine)
+	}
+}
+
+// OnPhase matches subroutine calls in the phases
+func OnPhase(phases ...Phase) Matcher {
+	return func(call Call) bool {
+		return slices.Contains(phases, call.Phase)
+	}
+}
+
+// OnClient matches all client calls
+func OnClient() Matcher {
+	return func(call Call) bool {
+		return call.Verb != ""
+	}
+}
+
+// OnVerb matches client calls with the verbs, e.g. update or patch/status
+func OnVerb(verbs ...string) Matcher {
+	return func(call Call) bool {
+		return slices.Contains(verbs, call.Verb)
+	}
+}
+
+// OnGVK matches calls for objects of the group version kind
+func OnGVK(gvk schema.GroupVersionKind) Matcher {
+	return func(call Call) bool {
+		return call.GVK == gvk
+	}
+}
+
+// OnKey matches calls for the object
+func OnKey(key client.ObjectKey) Matcher {
+	return func(call Call) bool {
+		return call.Key == key
+	}
+}
+
+// Fault is injected into a call. Subroutine calls return the error as OperatorError, client calls return the error.
+type Fault struct {
+	err    func(call Call) error
+	retry  bool
+	sentry bool
+	panic  any
+}
+
+// Conflict returns a conflict error of the API server
+func Conflict() Fault {
+	return Fault{retry: true, err: func(call Call) error {
+		return kerrors.NewConflict(groupResourceOf(call.GVK), call.Key.Name, errors.New("injected conflict"))
+	}}
+}
+
+// Timeout returns a timeout error of the API server
+func Timeout() Fault {
+	return Fault{retry: true, err: func(call Call) error {
+		return kerrors.NewTimeoutError(fmt.Sprintf("injected timeout for %s", describe(call)), 0)
+	}}
+}
+
+// Error returns the error, subroutine calls return it as retriable OperatorError
+func Error(err error) Fault {
+	return Fault{retry: true, err: func(Call) error {
+		return err
+	}}
+}
+
+// OperatorError returns the error as OperatorError with the retry and sentry flags. Client calls return the error.
+func OperatorError(err error, retry bool, sentry bool) Fault {
+	return Fault{retry: retry, sentry: sentry, err: func(Call) error {
+		return err
+	}}
+}
+
+// Panic panics with the value
+func Panic(value any) Fault {
+	return Fault{panic: value}
+}
+
+// Rule injects a fault into matching calls
+type Rule struct {
+	// Match restricts the rule to calls matching all matchers, an empty list matches all calls
+	Match []Matcher
+	// Calls restricts the rule to the n-th matching calls, counted from 1. An empty list selects all matching calls.
+	Calls []int
+	// Probability injects the fault into a selected call with the probability, 0 injects into every selected call
+	Probability float64
+	// Times limits the number of injections, 0 is unlimited
+	Times int
+	Fault Fault
+}
+
+func (r Rule) validate() error {
+	if r.Probability < 0 || r.Probability > 1 {
+		return fmt.Errorf("the probability should be between 0 and 1")
+	}
+	if r.Times < 0 {
+		return fmt.Errorf("the times shouldn't be negative")
+	}
+	if r.Fault.err == nil && r.Fault.panic == nil {
+		return fmt.Errorf("the rule has no fault")
+	}
+	return nil
+}
+
+type Config struct {
+	// Seed seeds the random source of the probabilities, so that runs are reproducible
+	Seed uint64
+	// Rules are the initial rules of the injector
+	Rules []Rule
+}
+
+type Option func(*Config)
+
+func WithSeed(seed uint64) Option {
+	return func(c *Config) {
+		c.Seed = seed
+	}
+}
+
+func WithRules(rules ...Rule) Option {
+	return func(c *Config) {
+		c.Rules = append(c.Rules, rules...)
+	}
+}
+
+// Injection records a call a fault was injected into
+type Injection struct {
+	Call Call
+	// Rule is the index of the injecting rule
+	Rule int
+	Err  error
+}
+
+// Injector injects the faults of its rules into the calls of the subroutines and clients it wraps. The rules are
+// evaluated in order and the first rule selecting a call injects its fault, later rules do not see the call.
+type Injector struct {
+	mu         sync.Mutex
+	rules      []*ruleState
+	rand       *rand.Rand
+	injections []Injection
+}
+
+type ruleState struct {
+	Rule
+	matched  int
+	injected int
+}
+
+func NewInjector(opts ...Option) (*Injector, error) {
+	cfg := Config{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	i := &Injector{rand: rand.New(rand.NewPCG(cfg.Seed, cfg.Seed))}
+	if err := i.Add(cfg.Rules...); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// Add adds rules to the injector
+func (i *Injector) Add(rules ...Rule) error {
+	for _, r := range rules {
+		if err := r.validate(); err != nil {
+			return err
+		}
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, r := range rules {
+		i.rules = append(i.rules, &ruleState{Rule: r})
+	}
+	return nil
+}
+
+// Reset removes all rules and recorded injections
+func (i *Injector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.rules = nil
+	i.injections = nil
+}
+
+// Injections returns the recorded injections in order
+func (i *Injector) Injections() []Injection {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return slices.Clone(i.injections)
+}
+
+// inject returns the injection and the fault selected for the call, if any, and records the injection
+func (i *Injector) inject(call Call) (Injection, Fault, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for index, r := range i.rules {
+		if !r.matches(call) {
+			continue
+		}
+		r.matched++
+		if len(r.Calls) > 0 && !slices.Contains(r.Calls, r.matched) {
+			continue
+		}
+		if r.Times > 0 && r.injected >= r.Times {
+			continue
+		}
+		if r.Probability > 0 && i.rand.Float64() >= r.Probability {
+			continue
+		}
+		r.injected++
+		var err error
+		if r.Fault.err != nil {
+			err = r.Fault.err(call)
+		}
+		injection := Injection{Call: call, Rule: index, Err: err}
+		i.injections = append(i.injections, injection)
+		return injection, r.Fault, true
+	}
+	return Injection{}, Fault{}, false
+}
+
+func (r *ruleState) matches(call Call) bool {
+	for _, match := range r.Match {
+		if !match(call) {
+			return false
+		}
+	}
+	return true
+}
+
+// injectError panics or returns the error of the fault selected for the call
+func (i *Injector) injectError(call Call) error {
+	injection, fault, ok := i.inject(call)
+	if !ok {
+		return nil
+	}
+	if fault.panic != nil {
+		panic(fault.panic)
+	}
+	return injection.Err
+}
+
+// injectOperatorError panics or returns the error of the fault selected for the call as OperatorError
+func (i *Injector) injectOperatorError(call Call) pmerrors.OperatorError {
+	injection, fault, ok := i.inject(call)
+	if !ok {
+		return nil
+	}
+	if fault.panic != nil {
+		panic(fault.panic)
+	}
+	return pmerrors.NewOperatorError(injection.Err, fault.retry, fault.sentry)
+}
+
+func groupResourceOf(gvk schema.GroupVersionKind) schema.GroupResource {
+	return schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(gvk.Kind)}
+}
+
+func describe(call Call) string {
+	if call.Subroutine != "" {
+		return fmt.Sprintf("%s of subroutine %s", call.Phase, call.Subroutine)
+	}
+	return fmt.Sprintf("%s %s %s", call.Verb, call.GVK.String(), call.Key.String())
+}
@@ -0,0 +1,241 @@
+package fault
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/harness"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	pmerrors "github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+type terminatingSubroutine struct {
+	pmtesting.FinalizerSubroutine
+}
+
+func (terminatingSubroutine) Terminate(context.Context, runtimeobject.RuntimeObject) (ctrl.Result, pmerrors.OperatorError) {
+	return ctrl.Result{}, nil
+}
+
+func TestNewInjector(t *testing.T) {
+	tests := []struct {
+		name string
+		rule Rule
+		err  string
+	}{
+		{name: "valid rule", rule: Rule{Fault: Conflict()}},
+		{name: "probability above 1", rule: Rule{Probability: 1.5, Fault: Conflict()}, err: "the probability should be between 0 and 1"},
+		{name: "negative times", rule: Rule{Times: -1, Fault: Conflict()}, err: "the times shouldn't be negative"},
+		{name: "missing fault", rule: Rule{}, err: "the rule has no fault"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewInjector(WithRules(test.rule))
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestInjectorSubroutine(t *testing.T) {
+	ctx := context.Background()
+	instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+
+	t.Run("injects into the n-th matching call", func(t *testing.T) {
+		// Arrange
+		injector, err := NewInjector(WithRules(Rule{
+			Match: []Matcher{OnSubroutine("changeStatus"), OnPhase(PhaseProcess)},
+			Calls: []int{2},
+			Fault: OperatorError(errors.New("boom"), false, true),
+		}))
+		require.NoError(t, err)
+		s := injector.Subroutine(pmtesting.FinalizerSubroutine{})
+
+		// Act
+		_, first := s.Process(ctx, instance)
+		_, second := s.Process(ctx, instance)
+		_, third := s.Process(ctx, instance)
+
+		// Assert
+		assert.Nil(t, first)
+		require.NotNil(t, second)
+		assert.EqualError(t, second.Err(), "boom")
+		assert.False(t, second.Retry())
+		assert.True(t, second.Sentry())
+		assert.Nil(t, third)
+		require.Len(t, injector.Injections(), 1)
+		assert.Equal(t, Call{Subroutine: "changeStatus", Phase: PhaseProcess, Key: client.ObjectKeyFromObject(instance)}, injector.Injections()[0].Call)
+	})
+
+	t.Run("limits the number of injections", func(t *testing.T) {
+		// Arrange
+		injector, err := NewInjector(WithRules(Rule{Match: []Matcher{OnPhase(PhaseFinalize)}, Times: 2, Fault: Conflict()}))
+		require.NoError(t, err)
+		s := injector.Subroutine(pmtesting.FinalizerSubroutine{})
+
+		// Act
+		var errs []pmerrors.OperatorError
+		for range 3 {
+			_, err := s.Finalize(ctx, instance)
+			errs = append(errs, err)
+		}
+		_, processErr := s.Process(ctx, instance)
+
+		// Assert
+		require.NotNil(t, errs[0])
+		assert.True(t, kerrors.IsConflict(errs[0].Err()))
+		assert.True(t, errs[0].Retry())
+		require.NotNil(t, errs[1])
+		assert.Nil(t, errs[2])
+		assert.Nil(t, processErr)
+	})
+
+	t.Run("injects with a reproducible probability", func(t *testing.T) {
+		// Arrange
+		run := func() []bool {
+			injector, err := NewInjector(WithSeed(42), WithRules(Rule{Probability: 0.5, Fault: Timeout()}))
+			require.NoError(t, err)
+			s := injector.Subroutine(pmtesting.FinalizerSubroutine{})
+			var failed []bool
+			for range 20 {
+				_, err := s.Process(ctx, instance)
+				failed = append(failed, err != nil)
+			}
+			return failed
+		}
+
+		// Act
+		first := run()
+		second := run()
+
+		// Assert
+		assert.Equal(t, first, second)
+		assert.Contains(t, first, true)
+		assert.Contains(t, first, false)
+	})
+
+	t.Run("panics", func(t *testing.T) {
+		// Arrange
+		injector, err := NewInjector(WithRules(Rule{Fault: Panic("injected panic")}))
+		require.NoError(t, err)
+		s := injector.Subroutine(pmtesting.FinalizerSubroutine{})
+
+		// Act & Assert
+		assert.PanicsWithValue(t, "injected panic", func() {
+			_, _ = s.Process(ctx, instance)
+		})
+	})
+
+	t.Run("keeps the optional interfaces of the subroutine", func(t *testing.T) {
+		// Arrange
+		injector, err := NewInjector(WithRules(Rule{Match: []Matcher{OnPhase(PhaseTerminate)}, Fault: Error(errors.New("terminate failed"))}))
+		require.NoError(t, err)
+
+		// Act
+		plain := injector.Subroutine(pmtesting.FinalizerSubroutine{})
+		terminating := injector.Subroutine(terminatingSubroutine{})
+
+		// Assert
+		_, ok := plain.(subroutine.Terminator)
+		assert.False(t, ok)
+		_, ok = plain.(subroutine.Initializer)
+		assert.False(t, ok)
+		terminator, ok := terminating.(subroutine.Terminator)
+		require.True(t, ok)
+		_, ok = terminating.(subroutine.InputHasher)
+		assert.False(t, ok)
+		_, terminateErr := terminator.Terminate(ctx, instance)
+		require.NotNil(t, terminateErr)
+		assert.EqualError(t, terminateErr.Err(), "terminate failed")
+		assert.Equal(t, "changeStatus", terminating.GetName())
+	})
+}
+
+func TestInjectorClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("injects into matching verbs and kinds", func(t *testing.T) {
+		// Arrange
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "bar"}}
+		injector, err := NewInjector(WithRules(Rule{
+			Match: []Matcher{OnVerb("update"), OnGVK(corev1.SchemeGroupVersion.WithKind("ConfigMap"))},
+			Calls: []int{1},
+			Fault: Conflict(),
+		}))
+		require.NoError(t, err)
+		cl := injector.Client(fake.NewClientBuilder().WithObjects(cm).Build())
+
+		// Act
+		getErr := cl.Get(ctx, client.ObjectKeyFromObject(cm), cm)
+		cm.Data = map[string]string{"key": "value"}
+		firstErr := cl.Update(ctx, cm)
+		secondErr := cl.Update(ctx, cm)
+
+		// Assert
+		assert.NoError(t, getErr)
+		assert.True(t, kerrors.IsConflict(firstErr))
+		assert.NoError(t, secondErr)
+		assert.Equal(t, Call{Verb: "update", GVK: corev1.SchemeGroupVersion.WithKind("ConfigMap"), Key: client.ObjectKey{Namespace: "bar", Name: "cm"}}, injector.Injections()[0].Call)
+	})
+
+	t.Run("injects into status writes", func(t *testing.T) {
+		// Arrange
+		instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+		injector, err := NewInjector(WithRules(Rule{Match: []Matcher{OnVerb("update/status")}, Fault: Timeout()}))
+		require.NoError(t, err)
+		cl := injector.Client(pmtesting.CreateFakeClient(t, instance))
+
+		// Act
+		updateErr := cl.Update(ctx, instance)
+		statusErr := cl.Status().Update(ctx, instance)
+
+		// Assert
+		assert.NoError(t, updateErr)
+		assert.True(t, kerrors.IsTimeout(statusErr))
+	})
+}
+
+func TestInjectorConvergence(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	log := testlogger.New().Logger
+	instance := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+	injector, err := NewInjector(WithRules(
+		Rule{Match: []Matcher{OnPhase(PhaseProcess)}, Calls: []int{1}, Fault: Timeout()},
+		Rule{Match: []Matcher{OnClient(), OnVerb("update", "patch")}, Times: 1, Fault: Conflict()},
+	))
+	require.NoError(t, err)
+	cl := injector.Client(pmtesting.CreateFakeClient(t, instance))
+	mgr := &pmtesting.TestLifecycleManager{Logger: log, SubroutinesArr: injector.Subroutines([]subroutine.Subroutine{pmtesting.FinalizerSubroutine{}})}
+	h, err := harness.New(cl, mgr, instance)
+	require.NoError(t, err)
+
+	// Act
+	steps, err := h.RunUntilSteady(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, injector.Injections(), 2)
+	assert.Error(t, steps[0].Err)
+	assert.True(t, steps[len(steps)-1].Steady())
+	final, err := h.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "other string", final.(*pmtesting.TestApiObject).Status.Some)
+	assert.Equal(t, []string{pmtesting.SubroutineFinalizer}, final.GetFinalizers())
+}
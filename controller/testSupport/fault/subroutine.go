@@ -0,0 +1,159 @@
+package fault
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/subroutine"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/watch"
+	"github.com/platform-mesh/golang-commons/errors"
+)
+
+// Subroutine wraps the subroutine to inject faults into its calls. The wrapper implements the optional
+// subroutine.Terminator, subroutine.Initializer and subroutine.InputHasher interfaces only if the subroutine does.
+func (i *Injector) Subroutine(s subroutine.Subroutine) subroutine.Subroutine {
+	f := &faultySubroutine{Subroutine: s, injector: i}
+	_, terminator := s.(subroutine.Terminator)
+	_, initializer := s.(subroutine.Initializer)
+	_, hasher := s.(subroutine.InputHasher)
+	switch {
+	case terminator && initializer && hasher:
+		return struct {
+			*faultySubroutine
+			faultyTerminator
+			faultyInitializer
+			faultyInputHasher
+		}{f, faultyTerminator{f}, faultyInitializer{f}, faultyInputHasher{f}}
+	case terminator && initializer:
+		return struct {
+			*faultySubroutine
+			faultyTerminator
+			faultyInitializer
+		}{f, faultyTerminator{f}, faultyInitializer{f}}
+	case terminator && hasher:
+		return struct {
+			*faultySubroutine
+			faultyTerminator
+			faultyInputHasher
+		}{f, faultyTerminator{f}, faultyInputHasher{f}}
+	case initializer && hasher:
+		return struct {
+			*faultySubroutine
+			faultyInitializer
+			faultyInputHasher
+		}{f, faultyInitializer{f}, faultyInputHasher{f}}
+	case terminator:
+		return struct {
+			*faultySubroutine
+			faultyTerminator
+		}{f, faultyTerminator{f}}
+	case initializer:
+		return struct {
+			*faultySubroutine
+			faultyInitializer
+		}{f, faultyInitializer{f}}
+	case hasher:
+		return struct {
+			*faultySubroutine
+			faultyInputHasher
+		}{f, faultyInputHasher{f}}
+	}
+	return f
+}
+
+// Subroutines wraps all subroutines
+func (i *Injector) Subroutines(subroutines []subroutine.Subroutine) []subroutine.Subroutine {
+	wrapped := make([]subroutine.Subroutine, len(subroutines))
+	for index, s := range subroutines {
+		wrapped[index] = i.Subroutine(s)
+	}
+	return wrapped
+}
+
+type faultySubroutine struct {
+	subroutine.Subroutine
+	injector *Injector
+}
+
+func (s *faultySubroutine) inject(phase Phase, instance runtimeobject.RuntimeObject) errors.OperatorError {
+	return s.injector.injectOperatorError(Call{
+		Subroutine: s.GetName(),
+		Phase:      phase,
+		GVK:        instance.GetObjectKind().GroupVersionKind(),
+		Key:        client.ObjectKeyFromObject(instance),
+	})
+}
+
+func (s *faultySubroutine) Process(ctx context.Context, instance runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	if err := s.inject(PhaseProcess, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return s.Subroutine.Process(ctx, instance)
+}
+
+func (s *faultySubroutine) Finalize(ctx context.Context, instance runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	if err := s.inject(PhaseFinalize, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return s.Subroutine.Finalize(ctx, instance)
+}
+
+// Watches returns the watches of the subroutine, which are only collected from subroutine.Watcher implementations
+func (s *faultySubroutine) Watches() []watch.Watch {
+	if w, ok := s.Subroutine.(subroutine.Watcher); ok {
+		return w.Watches()
+	}
+	return nil
+}
+
+// FinalizePriority returns the finalize priority of the subroutine, 0 is the default priority
+func (s *faultySubroutine) FinalizePriority() int {
+	if p, ok := s.Subroutine.(subroutine.FinalizePrioritizer); ok {
+		return p.FinalizePriority()
+	}
+	return 0
+}
+
+// FinalizeAfter returns the finalize dependencies of the subroutine
+func (s *faultySubroutine) FinalizeAfter() []string {
+	if d, ok := s.Subroutine.(subroutine.FinalizeDependent); ok {
+		return d.FinalizeAfter()
+	}
+	return nil
+}
+
+type faultyTerminator struct {
+	s *faultySubroutine
+}
+
+func (t faultyTerminator) Terminate(ctx context.Context, instance runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	if err := t.s.inject(PhaseTerminate, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return t.s.Subroutine.(subroutine.Terminator).Terminate(ctx, instance)
+}
+
+type faultyInitializer struct {
+	s *faultySubroutine
+}
+
+func (i faultyInitializer) Initialize(ctx context.Context, instance runtimeobject.RuntimeObject) (ctrl.Result, errors.OperatorError) {
+	if err := i.s.inject(PhaseInitialize, instance); err != nil {
+		return ctrl.Result{}, err
+	}
+	return i.s.Subroutine.(subroutine.Initializer).Initialize(ctx, instance)
+}
+
+type faultyInputHasher struct {
+	s *faultySubroutine
+}
+
+func (h faultyInputHasher) InputHash(ctx context.Context, instance runtimeobject.RuntimeObject) (string, errors.OperatorError) {
+	if err := h.s.inject(PhaseInputHash, instance); err != nil {
+		return "", err
+	}
+	return h.s.Subroutine.(subroutine.InputHasher).InputHash(ctx, instance)
+}
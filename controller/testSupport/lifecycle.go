@@ -6,6 +6,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
@@ -23,15 +24,32 @@ type TestLifecycleManager struct {
 	prepareContextFunc api.PrepareContextFunc
 	terminator         string
 	initializer        string
+	legacyFinalizers   []api.LegacyFinalizer
+	eventRecorder      events.EventRecorder
+	reportManager      api.ReportManager
+	reconcileTracker   api.ReconcileTracker
+	queuePolicy        api.QueuePolicy
+	skipUnchanged      bool
+	protectionRequeue  time.Duration
+	instrumentation    api.ClientInstrumentation
+	readOnly           bool
+	readOnlyDryRun     bool
 }
 
 func (l *TestLifecycleManager) Config() api.Config {
 	return api.Config{
 		ControllerName: "test-controller",
 		OperatorName:   "test-operator",
-		ReadOnly:       false,
+		ReadOnly:       l.readOnly,
+		ReadOnlyDryRun: l.readOnlyDryRun,
 	}
 }
+
+func (l *TestLifecycleManager) WithReadOnly(dryRun bool) *TestLifecycleManager {
+	l.readOnly = true
+	l.readOnlyDryRun = dryRun
+	return l
+}
 func (l *TestLifecycleManager) Log() *logger.Logger                     { return l.Logger }
 func (l *TestLifecycleManager) Spreader() api.SpreadManager             { return l.spreader }
 func (l *TestLifecycleManager) ConditionsManager() api.ConditionManager { return l.conditionsManager }
@@ -41,6 +59,67 @@ func (l *TestLifecycleManager) PrepareContextFunc() api.PrepareContextFunc {
 func (l *TestLifecycleManager) Subroutines() []subroutine.Subroutine { return l.SubroutinesArr }
 func (l *TestLifecycleManager) Terminator() string                   { return l.terminator }
 func (l *TestLifecycleManager) Initializer() string                  { return l.initializer }
+func (l *TestLifecycleManager) LegacyFinalizers() []api.LegacyFinalizer {
+	return l.legacyFinalizers
+}
+func (l *TestLifecycleManager) EventRecorder() events.EventRecorder { return l.eventRecorder }
+
+func (l *TestLifecycleManager) ReportManager() api.ReportManager { return l.reportManager }
+
+func (l *TestLifecycleManager) WithReportManager(reportManager api.ReportManager) *TestLifecycleManager {
+	l.reportManager = reportManager
+	return l
+}
+
+func (l *TestLifecycleManager) SkipUnchangedSubroutines() bool { return l.skipUnchanged }
+
+func (l *TestLifecycleManager) WithSkipUnchangedSubroutines() *TestLifecycleManager {
+	l.skipUnchanged = true
+	return l
+}
+
+func (l *TestLifecycleManager) DeletionBlockedRequeueAfter() time.Duration {
+	return l.protectionRequeue
+}
+
+func (l *TestLifecycleManager) WithDeletionBlockedRequeueAfter(d time.Duration) *TestLifecycleManager {
+	l.protectionRequeue = d
+	return l
+}
+
+func (l *TestLifecycleManager) ClientInstrumentation() api.ClientInstrumentation {
+	return l.instrumentation
+}
+
+func (l *TestLifecycleManager) WithClientInstrumentation(instrumentation api.ClientInstrumentation) *TestLifecycleManager {
+	l.instrumentation = instrumentation
+	return l
+}
+
+func (l *TestLifecycleManager) ReconcileTracker() api.ReconcileTracker { return l.reconcileTracker }
+
+func (l *TestLifecycleManager) WithReconcileTracker(tracker api.ReconcileTracker) *TestLifecycleManager {
+	l.reconcileTracker = tracker
+	return l
+}
+
+func (l *TestLifecycleManager) QueuePolicy() api.QueuePolicy { return l.queuePolicy }
+
+func (l *TestLifecycleManager) WithQueuePolicy(policy api.QueuePolicy) *TestLifecycleManager {
+	l.queuePolicy = policy
+	return l
+}
+
+func (l *TestLifecycleManager) WithLegacyFinalizers(finalizers ...api.LegacyFinalizer) *TestLifecycleManager {
+	l.legacyFinalizers = finalizers
+	return l
+}
+
+func (l *TestLifecycleManager) WithEventRecorder(recorder events.EventRecorder) *TestLifecycleManager {
+	l.eventRecorder = recorder
+	return l
+}
+
 func (l *TestLifecycleManager) WithTerminator(terminator string) *TestLifecycleManager {
 	l.terminator = terminator
 	return l
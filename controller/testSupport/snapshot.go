@@ -0,0 +1,339 @@
+package testSupport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+// NormalizedTimestamp replaces all timestamps in snapshots
+const NormalizedTimestamp = "<timestamp>"
+
+// RecordingClient records the objects created, updated, patched, applied or deleted through it, including the writes
+// of its Status and SubResource clients
+type RecordingClient struct {
+	client.Client
+	mu      sync.Mutex
+	written []client.Object
+}
+
+func NewRecordingClient(cl client.Client) *RecordingClient {
+	return &RecordingClient{Client: cl}
+}
+
+func (c *RecordingClient) record(obj client.Object) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, written := range c.written {
+		if sameObject(written, obj, c.Scheme()) {
+			return
+		}
+	}
+	c.written = append(c.written, obj.DeepCopyObject().(client.Object))
+}
+
+func (c *RecordingClient) recordApply(obj runtime.ApplyConfiguration) {
+	u := &unstructured.Unstructured{}
+	if ac, ok := obj.(interface {
+		GetAPIVersion() *string
+		GetKind() *string
+	}); ok && ac.GetAPIVersion() != nil && ac.GetKind() != nil {
+		u.SetGroupVersionKind(schema.FromAPIVersionAndKind(*ac.GetAPIVersion(), *ac.GetKind()))
+	}
+	if ac, ok := obj.(interface{ GetName() *string }); ok && ac.GetName() != nil {
+		u.SetName(*ac.GetName())
+	}
+	if ac, ok := obj.(interface{ GetNamespace() *string }); ok && ac.GetNamespace() != nil {
+		u.SetNamespace(*ac.GetNamespace())
+	}
+	c.record(u)
+}
+
+// Written returns the written objects in the order of their first write, as they were passed to the client
+func (c *RecordingClient) Written() []client.Object {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	written := make([]client.Object, len(c.written))
+	for i, obj := range c.written {
+		written[i] = obj.DeepCopyObject().(client.Object)
+	}
+	return written
+}
+
+func (c *RecordingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	if err == nil {
+		c.record(obj)
+	}
+	return err
+}
+
+func (c *RecordingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	err := c.Client.Update(ctx, obj, opts...)
+	if err == nil {
+		c.record(obj)
+	}
+	return err
+}
+
+func (c *RecordingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	if err == nil {
+		c.record(obj)
+	}
+	return err
+}
+
+func (c *RecordingClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+	err := c.Client.Apply(ctx, obj, opts...)
+	if err == nil {
+		c.recordApply(obj)
+	}
+	return err
+}
+
+func (c *RecordingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	if err == nil {
+		c.record(obj)
+	}
+	return err
+}
+
+func (c *RecordingClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *RecordingClient) SubResource(subResource string) client.SubResourceClient {
+	return &recordingSubResourceClient{SubResourceClient: c.Client.SubResource(subResource), client: c}
+}
+
+type recordingSubResourceClient struct {
+	client.SubResourceClient
+	client *RecordingClient
+}
+
+func (c *recordingSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	err := c.SubResourceClient.Create(ctx, obj, subResource, opts...)
+	if err == nil {
+		c.client.record(obj)
+	}
+	return err
+}
+
+func (c *recordingSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	err := c.SubResourceClient.Update(ctx, obj, opts...)
+	if err == nil {
+		c.client.record(obj)
+	}
+	return err
+}
+
+func (c *recordingSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	err := c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+	if err == nil {
+		c.client.record(obj)
+	}
+	return err
+}
+
+func (c *recordingSubResourceClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.SubResourceApplyOption) error {
+	err := c.SubResourceClient.Apply(ctx, obj, opts...)
+	if err == nil {
+		c.client.recordApply(obj)
+	}
+	return err
+}
+
+func sameObject(a, b client.Object, scheme *runtime.Scheme) bool {
+	if a.GetName() != b.GetName() || a.GetNamespace() != b.GetNamespace() {
+		return false
+	}
+	gvkA, errA := apiutil.GVKForObject(a, scheme)
+	gvkB, errB := apiutil.GVKForObject(b, scheme)
+	return errA == nil && errB == nil && gvkA == gvkB
+}
+
+type SnapshotConfig struct {
+	// Dir is the directory of the golden files
+	Dir string
+	// Update regenerates the golden files instead of comparing them, defaults to whether the UPDATE_SNAPSHOTS
+	// environment variable is set
+	Update bool
+	// Written adds the current state of all objects written through the recording client
+	Written *RecordingClient
+	// Logger adds the log messages selected by LogFilter
+	Logger    *testlogger.TestLogger
+	LogFilter func(message testlogger.LogMessage) bool
+	// Normalizers are applied to the unstructured content of every object after the default normalization
+	Normalizers []func(content map[string]any)
+}
+
+type SnapshotOption func(*SnapshotConfig)
+
+func WithSnapshotDir(dir string) SnapshotOption {
+	return func(c *SnapshotConfig) {
+		c.Dir = dir
+	}
+}
+
+func WithUpdate(update bool) SnapshotOption {
+	return func(c *SnapshotConfig) {
+		c.Update = update
+	}
+}
+
+func WithWrittenObjects(cl *RecordingClient) SnapshotOption {
+	return func(c *SnapshotConfig) {
+		c.Written = cl
+	}
+}
+
+// WithLogs adds the log messages of the logger to the snapshot, filter selects the messages and may be nil to select
+// all messages
+func WithLogs(l *testlogger.TestLogger, filter func(message testlogger.LogMessage) bool) SnapshotOption {
+	return func(c *SnapshotConfig) {
+		c.Logger = l
+		c.LogFilter = filter
+	}
+}
+
+func WithNormalizer(normalizer func(content map[string]any)) SnapshotOption {
+	return func(c *SnapshotConfig) {
+		c.Normalizers = append(c.Normalizers, normalizer)
+	}
+}
+
+// MatchSnapshot compares the current state of obj in the client, and optionally the written objects and selected log
+// messages, with the golden file <dir>/<name>.yaml. Server generated metadata is removed and timestamps, e.g. of
+// conditions, are replaced by NormalizedTimestamp.
+func MatchSnapshot(t *testing.T, name string, cl client.Client, obj client.Object, opts ...SnapshotOption) {
+	t.Helper()
+	cfg := SnapshotConfig{Dir: "testdata", Update: os.Getenv("UPDATE_SNAPSHOTS") != ""}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	snapshot := map[string]any{"object": snapshotObject(t, cl, obj, cfg.Normalizers)}
+	if cfg.Written != nil {
+		written := []any{}
+		for _, w := range cfg.Written.Written() {
+			written = append(written, snapshotObject(t, cl, w, cfg.Normalizers))
+		}
+		snapshot["written"] = written
+	}
+	if cfg.Logger != nil {
+		snapshot["logs"] = snapshotLogs(t, cfg.Logger, cfg.LogFilter)
+	}
+	actual, err := yaml.Marshal(snapshot)
+	require.NoError(t, err)
+
+	path := filepath.Join(cfg.Dir, name+".yaml")
+	if cfg.Update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, actual, 0o644))
+		return
+	}
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file missing, run the test with UPDATE_SNAPSHOTS=1 to create it")
+	assert.Equal(t, string(expected), string(actual), "snapshot %s differs, run the test with UPDATE_SNAPSHOTS=1 to update it", path)
+}
+
+// snapshotObject returns the normalized content of the object in the client, or its identity if it was deleted
+func snapshotObject(t *testing.T, cl client.Client, obj client.Object, normalizers []func(map[string]any)) map[string]any {
+	t.Helper()
+	gvk, err := apiutil.GVKForObject(obj, cl.Scheme())
+	require.NoError(t, err)
+
+	current := obj.DeepCopyObject().(client.Object)
+	err = cl.Get(context.Background(), client.ObjectKeyFromObject(obj), current)
+	if kerrors.IsNotFound(err) {
+		return map[string]any{
+			"apiVersion": gvk.GroupVersion().String(),
+			"kind":       gvk.Kind,
+			"metadata":   map[string]any{"name": obj.GetName(), "namespace": obj.GetNamespace()},
+			"deleted":    true,
+		}
+	}
+	require.NoError(t, err)
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	require.NoError(t, err)
+	content["apiVersion"] = gvk.GroupVersion().String()
+	content["kind"] = gvk.Kind
+	if metadata, ok := content["metadata"].(map[string]any); ok {
+		for _, field := range []string{"resourceVersion", "uid", "creationTimestamp", "managedFields", "selfLink"} {
+			delete(metadata, field)
+		}
+	}
+	normalizeTimestamps(content)
+	for _, normalize := range normalizers {
+		normalize(content)
+	}
+	return content
+}
+
+// normalizeTimestamps replaces all RFC 3339 timestamps in the content
+func normalizeTimestamps(content any) any {
+	switch value := content.(type) {
+	case map[string]any:
+		for key, v := range value {
+			value[key] = normalizeTimestamps(v)
+		}
+	case []any:
+		for i, v := range value {
+			value[i] = normalizeTimestamps(v)
+		}
+	case string:
+		if _, err := time.Parse(time.RFC3339, value); err == nil {
+			return NormalizedTimestamp
+		}
+	}
+	return content
+}
+
+func snapshotLogs(t *testing.T, l *testlogger.TestLogger, filter func(testlogger.LogMessage) bool) []any {
+	t.Helper()
+	messages, err := l.GetLogMessages()
+	require.NoError(t, err)
+	logs := []any{}
+	for _, message := range messages {
+		if filter != nil && !filter(message) {
+			continue
+		}
+		entry := map[string]any{"level": message.Level.String(), "message": message.Message}
+		if message.Error != nil {
+			entry["error"] = *message.Error
+		}
+		logs = append(logs, entry)
+	}
+	return logs
+}
+
+// LogMessageContains selects the log messages containing any of the texts
+func LogMessageContains(texts ...string) func(testlogger.LogMessage) bool {
+	return func(message testlogger.LogMessage) bool {
+		for _, text := range texts {
+			if strings.Contains(message.Message, text) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,74 @@
+package testSupport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func TestMatchSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("snapshots the object, the written objects and the logs", func(t *testing.T) {
+		// Arrange
+		log := testlogger.New().HideLogOutput()
+		instance := &TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", Labels: map[string]string{"app": "foo"}}}
+		deleted := &TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "deleted", Namespace: "bar"}}
+		cl := NewRecordingClient(CreateFakeClient(t, instance, deleted))
+
+		instance.Finalizers = []string{"finalizer"}
+		require.NoError(t, cl.Update(ctx, instance))
+		instance.Status.Some = "status"
+		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Complete", Message: "ready"})
+		require.NoError(t, cl.Status().Update(ctx, instance))
+		require.NoError(t, cl.Delete(ctx, deleted))
+		log.Info().Msg("reconciled instance")
+		log.Debug().Msg("ignored")
+
+		// Act & Assert
+		MatchSnapshot(t, "snapshot", cl, instance, WithWrittenObjects(cl), WithLogs(log, LogMessageContains("reconciled")))
+	})
+
+	t.Run("writes the golden file on update", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		instance := &TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+		cl := CreateFakeClient(t, instance)
+
+		// Act
+		MatchSnapshot(t, "nested/updated", cl, instance, WithSnapshotDir(dir), WithUpdate(true))
+
+		// Assert
+		content, err := os.ReadFile(filepath.Join(dir, "nested", "updated.yaml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "name: foo")
+		assert.NotContains(t, string(content), "resourceVersion")
+		MatchSnapshot(t, "nested/updated", cl, instance, WithSnapshotDir(dir))
+	})
+
+	t.Run("applies custom normalizers", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		instance := &TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar", Annotations: map[string]string{"random": "1234"}}}
+		cl := CreateFakeClient(t, instance)
+		dropAnnotations := func(content map[string]any) {
+			delete(content["metadata"].(map[string]any), "annotations")
+		}
+
+		// Act
+		MatchSnapshot(t, "normalized", cl, instance, WithSnapshotDir(dir), WithUpdate(true), WithNormalizer(dropAnnotations))
+
+		// Assert
+		content, err := os.ReadFile(filepath.Join(dir, "normalized.yaml"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "random")
+	})
+}
@@ -3,6 +3,8 @@ package testSupport
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
 )
 
 type TestApiObject struct {
@@ -12,12 +14,15 @@ type TestApiObject struct {
 	Status TestStatus `json:"status,omitempty"`
 }
 type TestStatus struct {
-	Some               string
-	Conditions         []metav1.Condition
-	NextReconcileTime  metav1.Time
-	ObservedGeneration int64
-	Terminators        []string `json:"terminators,omitempty"`
-	Initializers       []string `json:"initializers,omitempty"`
+	Some                string
+	Conditions          []metav1.Condition
+	NextReconcileTime   metav1.Time
+	ObservedGeneration  int64
+	Terminators         []string                           `json:"terminators,omitempty"`
+	Initializers        []string                           `json:"initializers,omitempty"`
+	SubroutineReports   []api.SubroutineReport             `json:"subroutineReports,omitempty"`
+	InputHashes         []api.SubroutineInputHash          `json:"inputHashes,omitempty"`
+	FinalizeCheckpoints []api.SubroutineFinalizeCheckpoint `json:"finalizeCheckpoints,omitempty"`
 }
 
 func (t *TestApiObject) DeepCopyObject() runtime.Object {
@@ -4,6 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
 )
 
 func TestTestApiObject_DeepCopy(t *testing.T) {
@@ -40,3 +43,19 @@ func TestTestApiObject_DeepCopy(t *testing.T) {
 		assert.Equal(t, instance, c)
 	})
 }
+
+func TestCommonStatusApiObject_DeepCopy(t *testing.T) {
+	// Arrange
+	instance := &CommonStatusApiObject{}
+	instance.SetConditions([]metav1.Condition{{Type: "Ready"}})
+	instance.SetSubroutineReports([]api.SubroutineReport{{Name: "subroutine"}})
+
+	// Act
+	c := instance.DeepCopy()
+	c.Status.Conditions[0].Type = "Changed"
+	c.Status.SubroutineReports[0].Name = "changed"
+
+	// Assert
+	assert.Equal(t, "Ready", instance.GetConditions()[0].Type)
+	assert.Equal(t, "subroutine", instance.GetSubroutineReports()[0].Name)
+}
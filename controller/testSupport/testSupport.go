@@ -8,7 +8,10 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 
 	"github.com/platform-mesh/golang-commons/context/keys"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/checkpoint"
 	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/watch"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -21,6 +24,11 @@ import (
 const FailureScenarioSubroutineFinalizer = "failuresubroutine"
 const ChangeStatusSubroutineFinalizer = "changestatus"
 
+// The Implement* fixtures below implement exactly one lifecycle interface each on top of TestApiObject, so that
+// tests can cover the behaviour of an interface in isolation and the absence of the others. lifecycle-gen always
+// emits the full set of accessors, so these stay hand-written; use CommonStatusApiObject or
+// ImplementConditionsAndSpreadReconciles for objects implementing all of them.
+
 type ImplementConditions struct {
 	TestApiObject `json:",inline"`
 }
@@ -177,31 +185,40 @@ func (c FailureScenarioSubroutine) GetName() string {
 	return "FailureScenarioSubroutine"
 }
 
-type ImplementConditionsAndSpreadReconciles struct {
+type ImplementSubroutineReports struct {
 	TestApiObject `json:",inline"`
 }
 
-func (m *ImplementConditionsAndSpreadReconciles) GetConditions() []metav1.Condition {
-	return m.Status.Conditions
+func (m *ImplementSubroutineReports) GetSubroutineReports() []api.SubroutineReport {
+	return m.Status.SubroutineReports
 }
-func (m *ImplementConditionsAndSpreadReconciles) SetConditions(conditions []metav1.Condition) {
-	m.Status.Conditions = conditions
+
+func (m *ImplementSubroutineReports) SetSubroutineReports(reports []api.SubroutineReport) {
+	m.Status.SubroutineReports = reports
 }
-func (m *ImplementConditionsAndSpreadReconciles) GetGeneration() int64 {
-	return m.Generation
+
+type ImplementSubroutineInputHashes struct {
+	TestApiObject `json:",inline"`
 }
-func (m *ImplementConditionsAndSpreadReconciles) GetObservedGeneration() int64 {
-	return m.Status.ObservedGeneration
+
+func (m *ImplementSubroutineInputHashes) GetSubroutineInputHashes() []api.SubroutineInputHash {
+	return m.Status.InputHashes
 }
-func (m *ImplementConditionsAndSpreadReconciles) SetObservedGeneration(g int64) {
-	m.Status.ObservedGeneration = g
+
+func (m *ImplementSubroutineInputHashes) SetSubroutineInputHashes(hashes []api.SubroutineInputHash) {
+	m.Status.InputHashes = hashes
 }
 
-func (m *ImplementConditionsAndSpreadReconciles) GetNextReconcileTime() metav1.Time {
-	return m.Status.NextReconcileTime
+type ImplementFinalizeCheckpoints struct {
+	TestApiObject `json:",inline"`
 }
-func (m *ImplementConditionsAndSpreadReconciles) SetNextReconcileTime(time metav1.Time) {
-	m.Status.NextReconcileTime = time
+
+func (m *ImplementFinalizeCheckpoints) GetFinalizeCheckpoints() []api.SubroutineFinalizeCheckpoint {
+	return m.Status.FinalizeCheckpoints
+}
+
+func (m *ImplementFinalizeCheckpoints) SetFinalizeCheckpoints(checkpoints []api.SubroutineFinalizeCheckpoint) {
+	m.Status.FinalizeCheckpoints = checkpoints
 }
 
 type ContextValueSubroutine struct {
@@ -227,3 +244,132 @@ func (f ContextValueSubroutine) Finalizers(instance runtimeobject.RuntimeObject)
 func (c ContextValueSubroutine) GetName() string {
 	return "ContextValueSubroutine"
 }
+
+type WatchingSubroutine struct {
+	WatchList []watch.Watch
+}
+
+func (w WatchingSubroutine) Process(_ context.Context, _ runtimeobject.RuntimeObject) (controllerruntime.Result, errors.OperatorError) {
+	return controllerruntime.Result{}, nil
+}
+
+func (w WatchingSubroutine) Finalize(_ context.Context, _ runtimeobject.RuntimeObject) (controllerruntime.Result, errors.OperatorError) {
+	return controllerruntime.Result{}, nil
+}
+
+func (w WatchingSubroutine) Finalizers(_ runtimeobject.RuntimeObject) []string {
+	return []string{}
+}
+
+func (w WatchingSubroutine) GetName() string {
+	return "WatchingSubroutine"
+}
+
+func (w WatchingSubroutine) Watches() []watch.Watch {
+	return w.WatchList
+}
+
+// HashingSubroutine counts its Process calls and returns the input hash computed from Status.Some
+type HashingSubroutine struct {
+	Processed *int
+	HashErr   errors.OperatorError
+}
+
+func (h HashingSubroutine) Process(_ context.Context, _ runtimeobject.RuntimeObject) (controllerruntime.Result, errors.OperatorError) {
+	*h.Processed++
+	return controllerruntime.Result{}, nil
+}
+
+func (h HashingSubroutine) Finalize(_ context.Context, _ runtimeobject.RuntimeObject) (controllerruntime.Result, errors.OperatorError) {
+	return controllerruntime.Result{}, nil
+}
+
+func (h HashingSubroutine) Finalizers(_ runtimeobject.RuntimeObject) []string {
+	return []string{}
+}
+
+func (h HashingSubroutine) GetName() string {
+	return "HashingSubroutine"
+}
+
+func (h HashingSubroutine) InputHash(_ context.Context, instance runtimeobject.RuntimeObject) (string, errors.OperatorError) {
+	if h.HashErr != nil {
+		return "", h.HashErr
+	}
+	return fmt.Sprintf("hash-%s", instance.(*ImplementSubroutineInputHashes).Status.Some), nil
+}
+
+// OrderedSubroutine declares its finalize priority and dependencies and uses its name as finalizer. Finalize
+// requests a requeue after FinalizeRequeueAfter if it is set.
+type OrderedSubroutine struct {
+	Name                 string
+	Priority             int
+	After                []string
+	FinalizeRequeueAfter time.Duration
+}
+
+func (o OrderedSubroutine) Process(_ context.Context, _ runtimeobject.RuntimeObject) (controllerruntime.Result, errors.OperatorError) {
+	return controllerruntime.Result{}, nil
+}
+
+func (o OrderedSubroutine) Finalize(_ context.Context, _ runtimeobject.RuntimeObject) (controllerruntime.Result, errors.OperatorError) {
+	return controllerruntime.Result{RequeueAfter: o.FinalizeRequeueAfter}, nil
+}
+
+func (o OrderedSubroutine) Finalizers(_ runtimeobject.RuntimeObject) []string {
+	return []string{o.Name}
+}
+
+func (o OrderedSubroutine) GetName() string {
+	return o.Name
+}
+
+func (o OrderedSubroutine) FinalizePriority() int {
+	return o.Priority
+}
+
+func (o OrderedSubroutine) FinalizeAfter() []string {
+	return o.After
+}
+
+const CheckpointingSubroutineFinalizer = "checkpointing"
+
+// CheckpointingSubroutine finalizes in steps, skipping the steps completed according to the finalize checkpoint. It
+// fails at the step FailAt while Fail is set and records the executed steps.
+type CheckpointingSubroutine struct {
+	Steps    []string
+	FailAt   string
+	Fail     bool
+	Executed []string
+}
+
+func (c *CheckpointingSubroutine) Process(_ context.Context, _ runtimeobject.RuntimeObject) (controllerruntime.Result, errors.OperatorError) {
+	return controllerruntime.Result{}, nil
+}
+
+func (c *CheckpointingSubroutine) Finalize(ctx context.Context, _ runtimeobject.RuntimeObject) (controllerruntime.Result, errors.OperatorError) {
+	progress := checkpoint.LoadCheckpointFromContext(ctx)
+	for _, step := range c.Steps {
+		if progress.StepDone(step) {
+			continue
+		}
+		if c.Fail && step == c.FailAt {
+			return controllerruntime.Result{}, errors.NewOperatorError(fmt.Errorf("failed at step %s", step), true, false)
+		}
+		c.Executed = append(c.Executed, step)
+		progress.CompleteStep(step)
+	}
+	return controllerruntime.Result{}, nil
+}
+
+func (c *CheckpointingSubroutine) CheckpointsFinalization() bool {
+	return true
+}
+
+func (c *CheckpointingSubroutine) Finalizers(_ runtimeobject.RuntimeObject) []string {
+	return []string{CheckpointingSubroutineFinalizer}
+}
+
+func (c *CheckpointingSubroutine) GetName() string {
+	return "checkpointing"
+}
@@ -0,0 +1,137 @@
+// Code generated by lifecycle-gen. DO NOT EDIT.
+
+package testSupport
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	lifecycleapi "github.com/platform-mesh/golang-commons/controller/lifecycle/api"
+)
+
+func (in *CommonStatusApiObject) GetConditions() []metav1.Condition {
+	return in.Status.CommonStatus.Conditions
+}
+
+func (in *CommonStatusApiObject) SetConditions(conditions []metav1.Condition) {
+	in.Status.CommonStatus.Conditions = conditions
+}
+
+func (in *CommonStatusApiObject) GetObservedGeneration() int64 {
+	return in.Status.CommonStatus.ObservedGeneration
+}
+
+func (in *CommonStatusApiObject) SetObservedGeneration(generation int64) {
+	in.Status.CommonStatus.ObservedGeneration = generation
+}
+
+func (in *CommonStatusApiObject) GetNextReconcileTime() metav1.Time {
+	return in.Status.CommonStatus.NextReconcileTime
+}
+
+func (in *CommonStatusApiObject) SetNextReconcileTime(time metav1.Time) {
+	in.Status.CommonStatus.NextReconcileTime = time
+}
+
+func (in *CommonStatusApiObject) GetSubroutineReports() []lifecycleapi.SubroutineReport {
+	return in.Status.CommonStatus.SubroutineReports
+}
+
+func (in *CommonStatusApiObject) SetSubroutineReports(reports []lifecycleapi.SubroutineReport) {
+	in.Status.CommonStatus.SubroutineReports = reports
+}
+
+func (in *CommonStatusApiObject) GetSubroutineInputHashes() []lifecycleapi.SubroutineInputHash {
+	return in.Status.CommonStatus.InputHashes
+}
+
+func (in *CommonStatusApiObject) SetSubroutineInputHashes(hashes []lifecycleapi.SubroutineInputHash) {
+	in.Status.CommonStatus.InputHashes = hashes
+}
+
+func (in *CommonStatusApiObject) GetFinalizeCheckpoints() []lifecycleapi.SubroutineFinalizeCheckpoint {
+	return in.Status.CommonStatus.FinalizeCheckpoints
+}
+
+func (in *CommonStatusApiObject) SetFinalizeCheckpoints(checkpoints []lifecycleapi.SubroutineFinalizeCheckpoint) {
+	in.Status.CommonStatus.FinalizeCheckpoints = checkpoints
+}
+
+func (in *CommonStatusApiObject) GetTerminators() []string {
+	return in.Status.CommonStatus.Terminators
+}
+
+func (in *CommonStatusApiObject) SetTerminators(terminators []string) {
+	in.Status.CommonStatus.Terminators = terminators
+}
+
+func (in *CommonStatusApiObject) GetInitializers() []string {
+	return in.Status.CommonStatus.Initializers
+}
+
+func (in *CommonStatusApiObject) SetInitializers(initializers []string) {
+	in.Status.CommonStatus.Initializers = initializers
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) GetConditions() []metav1.Condition {
+	return in.Status.CommonStatus.Conditions
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) SetConditions(conditions []metav1.Condition) {
+	in.Status.CommonStatus.Conditions = conditions
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) GetObservedGeneration() int64 {
+	return in.Status.CommonStatus.ObservedGeneration
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) SetObservedGeneration(generation int64) {
+	in.Status.CommonStatus.ObservedGeneration = generation
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) GetNextReconcileTime() metav1.Time {
+	return in.Status.CommonStatus.NextReconcileTime
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) SetNextReconcileTime(time metav1.Time) {
+	in.Status.CommonStatus.NextReconcileTime = time
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) GetSubroutineReports() []lifecycleapi.SubroutineReport {
+	return in.Status.CommonStatus.SubroutineReports
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) SetSubroutineReports(reports []lifecycleapi.SubroutineReport) {
+	in.Status.CommonStatus.SubroutineReports = reports
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) GetSubroutineInputHashes() []lifecycleapi.SubroutineInputHash {
+	return in.Status.CommonStatus.InputHashes
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) SetSubroutineInputHashes(hashes []lifecycleapi.SubroutineInputHash) {
+	in.Status.CommonStatus.InputHashes = hashes
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) GetFinalizeCheckpoints() []lifecycleapi.SubroutineFinalizeCheckpoint {
+	return in.Status.CommonStatus.FinalizeCheckpoints
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) SetFinalizeCheckpoints(checkpoints []lifecycleapi.SubroutineFinalizeCheckpoint) {
+	in.Status.CommonStatus.FinalizeCheckpoints = checkpoints
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) GetTerminators() []string {
+	return in.Status.CommonStatus.Terminators
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) SetTerminators(terminators []string) {
+	in.Status.CommonStatus.Terminators = terminators
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) GetInitializers() []string {
+	return in.Status.CommonStatus.Initializers
+}
+
+func (in *ImplementConditionsAndSpreadReconciles) SetInitializers(initializers []string) {
+	in.Status.CommonStatus.Initializers = initializers
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/protection"
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger"
+)
+
+// DeletionProtectionValidator rejects the deletion of objects carrying the deletion protection annotation
+type DeletionProtectionValidator struct{}
+
+// NewDeletionProtectionWebhook creates a webhook rejecting the deletion of protected objects. The webhook
+// configuration has to include the DELETE operation.
+func NewDeletionProtectionWebhook(name string, object runtimeobject.RuntimeObject, scheme *runtime.Scheme, log *logger.Logger) *Webhook {
+	return NewWebhook(name, object, scheme, log).WithValidators(DeletionProtectionValidator{})
+}
+
+func (v DeletionProtectionValidator) ValidateCreate(_ context.Context, _ runtimeobject.RuntimeObject) (field.ErrorList, errors.OperatorError) {
+	return nil, nil
+}
+
+func (v DeletionProtectionValidator) ValidateUpdate(_ context.Context, _, _ runtimeobject.RuntimeObject) (field.ErrorList, errors.OperatorError) {
+	return nil, nil
+}
+
+func (v DeletionProtectionValidator) ValidateDelete(_ context.Context, obj runtimeobject.RuntimeObject) (field.ErrorList, errors.OperatorError) {
+	if !protection.IsDeletionProtected(obj) {
+		return nil, nil
+	}
+	path := field.NewPath("metadata", "annotations").Key(protection.DeletionProtectionAnnotation)
+	return field.ErrorList{field.Forbidden(path, "the object is protected against deletion, remove the annotation to delete it")}, nil
+}
+
+func (v DeletionProtectionValidator) GetName() string {
+	return "DeletionProtectionValidator"
+}
@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/protection"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+func TestDeletionProtectionWebhook(t *testing.T) {
+	log := testlogger.New()
+	scheme := pmtesting.CreateScheme(t)
+	wh := NewDeletionProtectionWebhook("testapiobject", &pmtesting.TestApiObject{}, scheme, log.Logger)
+	harness := pmtesting.NewAdmissionHarness(t, wh.ValidatingHandler(), scheme)
+
+	protected := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Annotations: map[string]string{protection.DeletionProtectionAnnotation: "true"}}}
+	unprotected := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+
+	t.Run("rejects the deletion of protected objects", func(t *testing.T) {
+		resp := harness.Delete(protected)
+
+		assert.False(t, resp.Allowed)
+		require.NotNil(t, resp.Result.Details)
+		require.Len(t, resp.Result.Details.Causes, 1)
+		assert.Equal(t, "metadata.annotations[platform-mesh.io/deletion-protection]", resp.Result.Details.Causes[0].Field)
+	})
+
+	t.Run("allows the deletion of unprotected objects", func(t *testing.T) {
+		assert.True(t, harness.Delete(unprotected).Allowed)
+	})
+
+	t.Run("allows creating and updating protected objects", func(t *testing.T) {
+		assert.True(t, harness.Create(protected).Allowed)
+		assert.True(t, harness.Update(unprotected, protected).Allowed)
+	})
+}
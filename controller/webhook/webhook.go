@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	"github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/platform-mesh/golang-commons/sentry"
+)
+
+// Validator validates admission requests for an object. Validation failures are returned as field errors, which
+// are aggregated over all validators of a webhook. An OperatorError signals an internal failure and rejects the request.
+type Validator interface {
+	ValidateCreate(ctx context.Context, obj runtimeobject.RuntimeObject) (field.ErrorList, errors.OperatorError)
+	ValidateUpdate(ctx context.Context, oldObj, newObj runtimeobject.RuntimeObject) (field.ErrorList, errors.OperatorError)
+	ValidateDelete(ctx context.Context, obj runtimeobject.RuntimeObject) (field.ErrorList, errors.OperatorError)
+	GetName() string
+}
+
+// Defaulter sets default values on objects of create and update admission requests
+type Defaulter interface {
+	Default(ctx context.Context, obj runtimeobject.RuntimeObject) errors.OperatorError
+	GetName() string
+}
+
+// Manager is implemented by controller-runtime and multicluster-runtime managers
+type Manager interface {
+	GetWebhookServer() ctrlwebhook.Server
+}
+
+// Webhook composes validators and defaulters for a single object type and serves them as
+// validating and mutating admission handlers
+type Webhook struct {
+	name       string
+	object     runtimeobject.RuntimeObject
+	decoder    admission.Decoder
+	validators []Validator
+	defaulters []Defaulter
+	log        *logger.Logger
+}
+
+// NewWebhook creates a webhook named name for objects of the type of object
+func NewWebhook(name string, object runtimeobject.RuntimeObject, scheme *runtime.Scheme, log *logger.Logger) *Webhook {
+	return &Webhook{
+		name:    name,
+		object:  object,
+		decoder: admission.NewDecoder(scheme),
+		log:     log.MustChildLoggerWithAttributes("webhook", name),
+	}
+}
+
+// WithValidators adds validators, which are executed in the given order
+func (w *Webhook) WithValidators(validators ...Validator) *Webhook {
+	w.validators = append(w.validators, validators...)
+	return w
+}
+
+// WithDefaulters adds defaulters, which are executed in the given order
+func (w *Webhook) WithDefaulters(defaulters ...Defaulter) *Webhook {
+	w.defaulters = append(w.defaulters, defaulters...)
+	return w
+}
+
+func (w *Webhook) ValidatePath() string {
+	return fmt.Sprintf("/validate-%s", w.name)
+}
+
+func (w *Webhook) MutatePath() string {
+	return fmt.Sprintf("/mutate-%s", w.name)
+}
+
+// SetupWithManager registers the webhook with the webhook server of the manager
+func (w *Webhook) SetupWithManager(mgr Manager) {
+	w.Register(mgr.GetWebhookServer())
+}
+
+// Register registers the validating handler at ValidatePath and the mutating handler at MutatePath,
+// if the webhook has validators respectively defaulters
+func (w *Webhook) Register(server ctrlwebhook.Server) {
+	if len(w.validators) > 0 {
+		server.Register(w.ValidatePath(), &admission.Webhook{Handler: w.ValidatingHandler()})
+	}
+	if len(w.defaulters) > 0 {
+		server.Register(w.MutatePath(), &admission.Webhook{Handler: w.MutatingHandler()})
+	}
+}
+
+// ValidatingHandler returns an admission handler executing all validators
+func (w *Webhook) ValidatingHandler() admission.Handler {
+	return admission.HandlerFunc(w.validate)
+}
+
+// MutatingHandler returns an admission handler executing all defaulters
+func (w *Webhook) MutatingHandler() admission.Handler {
+	return admission.HandlerFunc(w.mutate)
+}
+
+func (w *Webhook) prepare(ctx context.Context, req admission.Request) (context.Context, *logger.Logger, sentry.Tags) {
+	log := w.log.MustChildLoggerWithAttributes("name", req.Name, "namespace", req.Namespace, "operation", string(req.Operation), "uid", string(req.UID))
+	sentryTags := sentry.Tags{"namespace": req.Namespace, "name": req.Name, "webhook": w.name}
+	ctx = logger.SetLoggerInContext(ctx, log)
+	ctx = sentry.ContextWithSentryTags(ctx, sentryTags)
+	return ctx, log, sentryTags
+}
+
+func (w *Webhook) validate(ctx context.Context, req admission.Request) admission.Response {
+	ctx, log, sentryTags := w.prepare(ctx, req)
+	log.Debug().Msg("start validation")
+
+	var obj, oldObj runtimeobject.RuntimeObject
+	var err error
+	switch req.Operation {
+	case admissionv1.Create:
+		obj, err = w.decode(req.Object)
+	case admissionv1.Update:
+		obj, err = w.decode(req.Object)
+		if err == nil {
+			oldObj, err = w.decode(req.OldObject)
+		}
+	case admissionv1.Delete:
+		obj, err = w.decode(req.OldObject)
+	default:
+		return admission.Allowed("")
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("failed to decode object")
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var allErrs field.ErrorList
+	for _, v := range w.validators {
+		validatorLogger := log.ChildLogger("validator", v.GetName())
+		vctx := logger.SetLoggerInContext(ctx, validatorLogger)
+
+		var errs field.ErrorList
+		var oErr errors.OperatorError
+		switch req.Operation {
+		case admissionv1.Create:
+			errs, oErr = v.ValidateCreate(vctx, obj)
+		case admissionv1.Update:
+			errs, oErr = v.ValidateUpdate(vctx, oldObj, obj)
+		case admissionv1.Delete:
+			errs, oErr = v.ValidateDelete(vctx, obj)
+		}
+		if oErr != nil {
+			return w.handleOperatorError(oErr, "validator ended with error", validatorLogger, sentryTags)
+		}
+		if len(errs) > 0 {
+			validatorLogger.Debug().Str("errors", errs.ToAggregate().Error()).Msg("validation failed")
+		}
+		allErrs = append(allErrs, errs...)
+	}
+
+	if len(allErrs) > 0 {
+		log.Info().Int("errors", len(allErrs)).Msg("request denied")
+		status := kerrors.NewInvalid(schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}, req.Name, allErrs).Status()
+		return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{Allowed: false, Result: &status}}
+	}
+
+	log.Debug().Msg("end validation")
+	return admission.Allowed("")
+}
+
+func (w *Webhook) mutate(ctx context.Context, req admission.Request) admission.Response {
+	ctx, log, sentryTags := w.prepare(ctx, req)
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	log.Debug().Msg("start defaulting")
+
+	obj, err := w.decode(req.Object)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to decode object")
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	for _, d := range w.defaulters {
+		defaulterLogger := log.ChildLogger("defaulter", d.GetName())
+		if oErr := d.Default(logger.SetLoggerInContext(ctx, defaulterLogger), obj); oErr != nil {
+			return w.handleOperatorError(oErr, "defaulter ended with error", defaulterLogger, sentryTags)
+		}
+	}
+
+	marshalled, err := json.Marshal(obj)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to marshal object")
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	log.Debug().Msg("end defaulting")
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshalled)
+}
+
+func (w *Webhook) decode(raw runtime.RawExtension) (runtimeobject.RuntimeObject, error) {
+	obj := w.object.DeepCopyObject().(runtimeobject.RuntimeObject)
+	if err := w.decoder.DecodeRaw(raw, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (w *Webhook) handleOperatorError(oErr errors.OperatorError, msg string, log *logger.Logger, sentryTags sentry.Tags) admission.Response {
+	if oErr.Sentry() {
+		sentry.CaptureError(oErr.Err(), sentryTags)
+	}
+	log.Error().Err(oErr.Err()).Msg(msg)
+	return admission.Errored(http.StatusInternalServerError, oErr.Err())
+}
@@ -0,0 +1,244 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	mcmanager "sigs.k8s.io/multicluster-runtime/pkg/manager"
+
+	"github.com/platform-mesh/golang-commons/controller/lifecycle/runtimeobject"
+	pmtesting "github.com/platform-mesh/golang-commons/controller/testSupport"
+	operrors "github.com/platform-mesh/golang-commons/errors"
+	"github.com/platform-mesh/golang-commons/logger"
+	"github.com/platform-mesh/golang-commons/logger/testlogger"
+)
+
+var _ Manager = manager.Manager(nil)
+var _ Manager = mcmanager.Manager(nil)
+
+type someValidator struct {
+	name string
+	err  operrors.OperatorError
+}
+
+func (v someValidator) ValidateCreate(ctx context.Context, obj runtimeobject.RuntimeObject) (field.ErrorList, operrors.OperatorError) {
+	logger.LoadLoggerFromContext(ctx).Info().Msg("validating create")
+	return v.validate(obj)
+}
+
+func (v someValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtimeobject.RuntimeObject) (field.ErrorList, operrors.OperatorError) {
+	if oldObj.(*pmtesting.TestApiObject).Status.Some != newObj.(*pmtesting.TestApiObject).Status.Some {
+		return field.ErrorList{field.Forbidden(field.NewPath("status", "some"), "immutable")}, nil
+	}
+	return nil, nil
+}
+
+func (v someValidator) ValidateDelete(_ context.Context, obj runtimeobject.RuntimeObject) (field.ErrorList, operrors.OperatorError) {
+	return v.validate(obj)
+}
+
+func (v someValidator) validate(obj runtimeobject.RuntimeObject) (field.ErrorList, operrors.OperatorError) {
+	if v.err != nil {
+		return nil, v.err
+	}
+	if obj.(*pmtesting.TestApiObject).Status.Some == "" {
+		return field.ErrorList{field.Required(field.NewPath("status", "some"), v.name)}, nil
+	}
+	return nil, nil
+}
+
+func (v someValidator) GetName() string { return v.name }
+
+type someDefaulter struct {
+	err operrors.OperatorError
+}
+
+func (d someDefaulter) Default(_ context.Context, obj runtimeobject.RuntimeObject) operrors.OperatorError {
+	if d.err != nil {
+		return d.err
+	}
+	instance := obj.(*pmtesting.TestApiObject)
+	if instance.Status.Some == "" {
+		instance.Status.Some = "default"
+	}
+	return nil
+}
+
+func (d someDefaulter) GetName() string { return "someDefaulter" }
+
+func TestWebhook_Validate(t *testing.T) {
+	log := testlogger.New()
+	scheme := pmtesting.CreateScheme(t)
+	obj := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+	valid := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}, Status: pmtesting.TestStatus{Some: "value"}}
+
+	t.Run("aggregates field errors of all validators", func(t *testing.T) {
+		// Arrange
+		wh := NewWebhook("test", &pmtesting.TestApiObject{}, scheme, log.Logger).
+			WithValidators(someValidator{name: "first"}, someValidator{name: "second"})
+		harness := pmtesting.NewAdmissionHarness(t, wh.ValidatingHandler(), scheme)
+
+		// Act
+		resp := harness.Create(obj)
+
+		// Assert
+		assert.False(t, resp.Allowed)
+		require.NotNil(t, resp.Result)
+		assert.Equal(t, int32(http.StatusUnprocessableEntity), resp.Result.Code)
+		require.NotNil(t, resp.Result.Details)
+		require.Len(t, resp.Result.Details.Causes, 2)
+		assert.Equal(t, "status.some", resp.Result.Details.Causes[0].Field)
+		assert.Contains(t, resp.Result.Message, "first")
+		assert.Contains(t, resp.Result.Message, "second")
+	})
+
+	t.Run("allows valid objects", func(t *testing.T) {
+		wh := NewWebhook("test", &pmtesting.TestApiObject{}, scheme, log.Logger).WithValidators(someValidator{name: "first"})
+		harness := pmtesting.NewAdmissionHarness(t, wh.ValidatingHandler(), scheme)
+
+		assert.True(t, harness.Create(valid).Allowed)
+	})
+
+	t.Run("passes old and new object on update", func(t *testing.T) {
+		wh := NewWebhook("test", &pmtesting.TestApiObject{}, scheme, log.Logger).WithValidators(someValidator{name: "first"})
+		harness := pmtesting.NewAdmissionHarness(t, wh.ValidatingHandler(), scheme)
+
+		assert.True(t, harness.Update(valid, valid).Allowed)
+		assert.False(t, harness.Update(obj, valid).Allowed)
+	})
+
+	t.Run("validates the old object on delete", func(t *testing.T) {
+		wh := NewWebhook("test", &pmtesting.TestApiObject{}, scheme, log.Logger).WithValidators(someValidator{name: "first"})
+		harness := pmtesting.NewAdmissionHarness(t, wh.ValidatingHandler(), scheme)
+
+		assert.False(t, harness.Delete(obj).Allowed)
+		assert.True(t, harness.Delete(valid).Allowed)
+	})
+
+	t.Run("rejects the request on validator errors", func(t *testing.T) {
+		// Arrange
+		testLog := testlogger.New()
+		err := operrors.NewOperatorError(errors.New("lookup failed"), true, false)
+		wh := NewWebhook("test", &pmtesting.TestApiObject{}, scheme, testLog.Logger).WithValidators(someValidator{name: "first", err: err})
+		harness := pmtesting.NewAdmissionHarness(t, wh.ValidatingHandler(), scheme)
+
+		// Act
+		resp := harness.Create(valid)
+
+		// Assert
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, int32(http.StatusInternalServerError), resp.Result.Code)
+		messages, logErr := testLog.GetErrorMessages()
+		require.NoError(t, logErr)
+		require.Len(t, messages, 1)
+		assert.Equal(t, "validator ended with error", messages[0].Message)
+	})
+
+	t.Run("sets the logger in the context", func(t *testing.T) {
+		testLog := testlogger.New()
+		wh := NewWebhook("test", &pmtesting.TestApiObject{}, scheme, testLog.Logger).WithValidators(someValidator{name: "first"})
+		harness := pmtesting.NewAdmissionHarness(t, wh.ValidatingHandler(), scheme)
+
+		harness.Create(valid)
+
+		messages, err := testLog.GetLogMessages()
+		require.NoError(t, err)
+		found := false
+		for _, m := range messages {
+			if m.Message == "validating create" {
+				found = true
+				assert.Equal(t, "first", m.Attributes["validator"])
+				assert.Equal(t, "foo", m.Attributes["name"])
+			}
+		}
+		assert.True(t, found)
+	})
+}
+
+func TestWebhook_Default(t *testing.T) {
+	log := testlogger.New()
+	scheme := pmtesting.CreateScheme(t)
+
+	t.Run("patches defaulted fields", func(t *testing.T) {
+		// Arrange
+		obj := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+		wh := NewWebhook("test", &pmtesting.TestApiObject{}, scheme, log.Logger).WithDefaulters(someDefaulter{})
+		harness := pmtesting.NewAdmissionHarness(t, wh.MutatingHandler(), scheme)
+
+		// Act
+		resp := harness.Create(obj)
+
+		// Assert
+		require.True(t, resp.Allowed)
+		require.NotEmpty(t, resp.Patches)
+		pmtesting.ApplyPatches(t, resp, obj)
+		assert.Equal(t, "default", obj.Status.Some)
+	})
+
+	t.Run("does not patch defaulted objects", func(t *testing.T) {
+		obj := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}, Status: pmtesting.TestStatus{Some: "value"}}
+		wh := NewWebhook("test", &pmtesting.TestApiObject{}, scheme, log.Logger).WithDefaulters(someDefaulter{})
+		harness := pmtesting.NewAdmissionHarness(t, wh.MutatingHandler(), scheme)
+
+		resp := harness.Update(obj, obj)
+
+		assert.True(t, resp.Allowed)
+		assert.Empty(t, resp.Patches)
+	})
+
+	t.Run("rejects the request on defaulter errors", func(t *testing.T) {
+		obj := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+		err := operrors.NewOperatorError(errors.New("failed"), false, false)
+		wh := NewWebhook("test", &pmtesting.TestApiObject{}, scheme, log.Logger).WithDefaulters(someDefaulter{err: err})
+		harness := pmtesting.NewAdmissionHarness(t, wh.MutatingHandler(), scheme)
+
+		resp := harness.Create(obj)
+
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, int32(http.StatusInternalServerError), resp.Result.Code)
+	})
+
+	t.Run("ignores deletions", func(t *testing.T) {
+		obj := &pmtesting.TestApiObject{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
+		wh := NewWebhook("test", &pmtesting.TestApiObject{}, scheme, log.Logger).WithDefaulters(someDefaulter{})
+		harness := pmtesting.NewAdmissionHarness(t, wh.MutatingHandler(), scheme)
+
+		resp := harness.Delete(obj)
+
+		assert.True(t, resp.Allowed)
+		assert.Empty(t, resp.Patches)
+	})
+}
+
+type testManager struct {
+	server ctrlwebhook.Server
+}
+
+func (m testManager) GetWebhookServer() ctrlwebhook.Server { return m.server }
+
+func TestWebhook_SetupWithManager(t *testing.T) {
+	log := testlogger.New()
+	scheme := pmtesting.CreateScheme(t)
+	mgr := testManager{server: ctrlwebhook.NewServer(ctrlwebhook.Options{})}
+
+	NewWebhook("testapiobject", &pmtesting.TestApiObject{}, scheme, log.Logger).
+		WithValidators(someValidator{name: "first"}).
+		SetupWithManager(mgr)
+
+	mux := mgr.server.WebhookMux()
+	for path, registered := range map[string]bool{"/validate-testapiobject": true, "/mutate-testapiobject": false} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, strings.NewReader("{}")))
+		assert.Equal(t, registered, rec.Code != http.StatusNotFound, path)
+	}
+}